@@ -0,0 +1,50 @@
+package client
+
+import (
+	"fmt"
+
+	Cli "github.com/docker/docker/cli"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/runconfig"
+)
+
+// CmdCheckpoint checkpoints the processes of one or more running containers.
+//
+// Usage: docker checkpoint [OPTIONS] CONTAINER [CONTAINER...]
+func (cli *DockerCli) CmdCheckpoint(args ...string) error {
+	cmd := Cli.Subcmd("checkpoint", []string{"CONTAINER [CONTAINER...]"}, "Checkpoint one or more running containers", true)
+	flImgDir := cmd.String([]string{"-image-dir"}, "", "Directory for storing checkpoint image files")
+	flWorkDir := cmd.String([]string{"-work-dir"}, "", "Directory for storing log file")
+	flLeaveRunning := cmd.Bool([]string{"-leave-running"}, false, "Leave the container running after checkpoint")
+	flTCP := cmd.Bool([]string{"-allow-tcp"}, false, "Allow checkpointing tcp connections")
+	flExtUnix := cmd.Bool([]string{"-allow-ext-unix"}, false, "Allow checkpointing external unix connections")
+	flShell := cmd.Bool([]string{"-allow-shell"}, false, "Allow checkpointing shell jobs")
+	flFileLocks := cmd.Bool([]string{"-allow-file-locks"}, false, "Allow checkpointing file locks")
+	cmd.Require(flag.Min, 1)
+
+	cmd.ParseFlags(args, true)
+
+	criuOpts := &runconfig.CriuConfig{
+		ImagesDirectory:         *flImgDir,
+		WorkDirectory:           *flWorkDir,
+		LeaveRunning:            *flLeaveRunning,
+		TCPEstablished:          *flTCP,
+		ExternalUnixConnections: *flExtUnix,
+		ShellJob:                *flShell,
+		FileLocks:               *flFileLocks,
+	}
+
+	var errNames []string
+	for _, name := range cmd.Args() {
+		if _, _, err := readBody(cli.call("POST", fmt.Sprintf("/containers/%s/checkpoint", name), criuOpts, nil)); err != nil {
+			fmt.Fprintf(cli.err, "%s\n", err)
+			errNames = append(errNames, name)
+		} else {
+			fmt.Fprintf(cli.out, "%s\n", name)
+		}
+	}
+	if len(errNames) > 0 {
+		return fmt.Errorf("Error: failed to checkpoint containers: %v", errNames)
+	}
+	return nil
+}
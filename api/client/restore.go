@@ -0,0 +1,55 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+
+	Cli "github.com/docker/docker/cli"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/runconfig"
+)
+
+// CmdRestore restores the processes of one or more checkpointed containers.
+//
+// Usage: docker restore [OPTIONS] CONTAINER [CONTAINER...]
+func (cli *DockerCli) CmdRestore(args ...string) error {
+	cmd := Cli.Subcmd("restore", []string{"CONTAINER [CONTAINER...]"}, "Restore one or more checkpointed containers", true)
+	flImgDir := cmd.String([]string{"-image-dir"}, "", "Directory to restore the container from")
+	flWorkDir := cmd.String([]string{"-work-dir"}, "", "Directory for restore log")
+	flTCP := cmd.Bool([]string{"-allow-tcp"}, false, "Allow restoring tcp connections")
+	flExtUnix := cmd.Bool([]string{"-allow-ext-unix"}, false, "Allow restoring external unix connections")
+	flShell := cmd.Bool([]string{"-allow-shell"}, false, "Allow restoring shell jobs")
+	flFileLocks := cmd.Bool([]string{"-allow-file-locks"}, false, "Allow restoring file locks")
+	flForce := cmd.Bool([]string{"-force"}, false, "Restore a container that was not checkpointed, e.g. from the images of another host")
+	cmd.Require(flag.Min, 1)
+
+	cmd.ParseFlags(args, true)
+
+	criuOpts := &runconfig.CriuConfig{
+		ImagesDirectory:         *flImgDir,
+		WorkDirectory:           *flWorkDir,
+		TCPEstablished:          *flTCP,
+		ExternalUnixConnections: *flExtUnix,
+		ShellJob:                *flShell,
+		FileLocks:               *flFileLocks,
+	}
+
+	v := url.Values{}
+	if *flForce {
+		v.Set("force", "1")
+	}
+
+	var errNames []string
+	for _, name := range cmd.Args() {
+		if _, _, err := readBody(cli.call("POST", fmt.Sprintf("/containers/%s/restore?%s", name, v.Encode()), criuOpts, nil)); err != nil {
+			fmt.Fprintf(cli.err, "%s\n", err)
+			errNames = append(errNames, name)
+		} else {
+			fmt.Fprintf(cli.out, "%s\n", name)
+		}
+	}
+	if len(errNames) > 0 {
+		return fmt.Errorf("Error: failed to restore containers: %v", errNames)
+	}
+	return nil
+}
@@ -53,7 +53,6 @@ func (cli *DockerCli) CmdRun(args ...string) error {
 
 		ErrConflictAttachDetach               = fmt.Errorf("Conflicting options: -a and -d")
 		ErrConflictRestartPolicyAndAutoRemove = fmt.Errorf("Conflicting options: --restart and --rm")
-		ErrConflictDetachAutoRemove           = fmt.Errorf("Conflicting options: --rm and -d")
 	)
 
 	config, hostConfig, cmd, err := runconfig.Parse(cmd, args)
@@ -78,6 +77,12 @@ func (cli *DockerCli) CmdRun(args ...string) error {
 		cmd.Usage()
 		return nil
 	}
+	if *flAutoRemove && hostConfig.RestartPolicy.Name != "" && !hostConfig.RestartPolicy.IsNone() {
+		return ErrConflictRestartPolicyAndAutoRemove
+	}
+	// The container is removed by the daemon when it exits, even if the
+	// client is gone by then.
+	hostConfig.AutoRemove = *flAutoRemove
 
 	if !*flDetach {
 		if err := cli.CheckTtyInput(config.AttachStdin, config.Tty); err != nil {
@@ -90,9 +95,6 @@ func (cli *DockerCli) CmdRun(args ...string) error {
 				return ErrConflictAttachDetach
 			}
 		}
-		if *flAutoRemove {
-			return ErrConflictDetachAutoRemove
-		}
 
 		config.AttachStdin = false
 		config.AttachStdout = false
@@ -133,9 +135,6 @@ func (cli *DockerCli) CmdRun(args ...string) error {
 			fmt.Fprintf(cli.out, "%s\n", createResponse.ID)
 		}()
 	}
-	if *flAutoRemove && (hostConfig.RestartPolicy.IsAlways() || hostConfig.RestartPolicy.IsOnFailure()) {
-		return ErrConflictRestartPolicyAndAutoRemove
-	}
 	// We need to instantiate the chan because the select needs it. It can
 	// be closed but can't be uninitialized.
 	hijacked := make(chan io.Closer)
@@ -190,13 +189,14 @@ func (cli *DockerCli) CmdRun(args ...string) error {
 		}
 	}
 
-	defer func() {
-		if *flAutoRemove {
-			if _, _, err = readBody(cli.call("DELETE", "/containers/"+createResponse.ID+"?v=1", nil, nil)); err != nil {
-				fmt.Fprintf(cli.err, "Error deleting container: %s\n", err)
-			}
+	// The exit code of a container removed by the daemon is read from its
+	// events, which must be watched before it is started.
+	var statusChan chan int
+	if *flAutoRemove && (config.AttachStdout || config.AttachStderr) {
+		if statusChan, err = waitExitOrRemoved(cli, createResponse.ID); err != nil {
+			return err
 		}
-	}()
+	}
 
 	//start the container
 	if _, _, err = readBody(cli.call("POST", "/containers/"+createResponse.ID+"/start", nil, nil)); err != nil {
@@ -226,15 +226,10 @@ func (cli *DockerCli) CmdRun(args ...string) error {
 	var status int
 
 	// Attached mode
-	if *flAutoRemove {
-		// Autoremove: wait for the container to finish, retrieve
-		// the exit code and remove the container
-		if _, _, err := readBody(cli.call("POST", "/containers/"+createResponse.ID+"/wait", nil, nil)); err != nil {
-			return err
-		}
-		if _, status, err = getExitCode(cli, createResponse.ID); err != nil {
-			return err
-		}
+	if statusChan != nil {
+		// Autoremove: wait for the container to exit and to be removed
+		// by the daemon
+		status = <-statusChan
 	} else {
 		// No Autoremove: Simply retrieve the exit code
 		if !config.Tty {
@@ -20,9 +20,11 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/api/types"
+	eventtypes "github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/autogen/dockerversion"
 	"github.com/docker/docker/cliconfig"
 	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/parsers/filters"
 	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/term"
@@ -271,6 +273,51 @@ func waitForExit(cli *DockerCli, containerID string) (int, error) {
 	return res.StatusCode, nil
 }
 
+// waitExitOrRemoved watches the events of a container removed by the daemon
+// when it exits, and sends its exit code on the returned channel once it has
+// been removed. The events are watched before the container is started, so
+// the exit code is not lost when the container is gone by the time the
+// container would be inspected.
+func waitExitOrRemoved(cli *DockerCli, containerID string) (chan int, error) {
+	f := filters.Args{
+		"type":      {eventtypes.ContainerEventType},
+		"container": {containerID},
+	}
+	filterJSON, err := filters.ToParam(f)
+	if err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	v.Set("filters", filterJSON)
+	serverResp, err := cli.call("GET", "/events?"+v.Encode(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	statusChan := make(chan int, 1)
+	go func() {
+		defer serverResp.body.Close()
+		status := -1
+		dec := json.NewDecoder(serverResp.body)
+		for {
+			var event eventtypes.Message
+			if err := dec.Decode(&event); err != nil {
+				logrus.Debugf("Error reading the events of container %s: %v", containerID, err)
+				break
+			}
+			if event.Action == "die" {
+				if code, err := strconv.Atoi(event.Actor.Attributes["exitCode"]); err == nil {
+					status = code
+				}
+			} else if event.Action == "destroy" {
+				break
+			}
+		}
+		statusChan <- status
+	}()
+	return statusChan, nil
+}
+
 // getExitCode perform an inspect on the container. It returns
 // the running state and the exit code.
 func getExitCode(cli *DockerCli, containerID string) (bool, int, error) {
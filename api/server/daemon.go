@@ -5,13 +5,14 @@ import (
 	"net/http"
 	"runtime"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/api/types"
+	eventtypes "github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/autogen/dockerversion"
+	"github.com/docker/docker/daemon/events"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/parsers/filters"
@@ -84,66 +85,48 @@ func (s *Server) getEvents(version version.Version, w http.ResponseWriter, r *ht
 	if err != nil {
 		return err
 	}
+	filter := events.NewFilter(ef)
 
-	isFiltered := func(field string, filter []string) bool {
-		if len(field) == 0 {
-			return false
-		}
-		if len(filter) == 0 {
-			return false
-		}
-		for _, v := range filter {
-			if v == field {
-				return false
-			}
-			if strings.Contains(field, ":") {
-				image := strings.Split(field, ":")
-				if image[0] == v {
-					return false
-				}
-			}
-		}
-		return true
-	}
-
-	d := s.daemon
-	es := d.EventsService
+	es := s.daemon.EventsService
 	w.Header().Set("Content-Type", "application/json")
 	outStream := ioutils.NewWriteFlusher(w)
 	outStream.Write(nil) // make sure response is sent immediately
 	enc := json.NewEncoder(outStream)
 
-	getContainerID := func(cn string) string {
-		c, err := d.Get(cn)
-		if err != nil {
-			return ""
-		}
-		return c.ID
-	}
-
-	sendEvent := func(ev *jsonmessage.JSONMessage) error {
-		//incoming container filter can be name,id or partial id, convert and replace as a full container id
-		for i, cn := range ef["container"] {
-			ef["container"][i] = getContainerID(cn)
-		}
-
-		if isFiltered(ev.Status, ef["event"]) || (isFiltered(ev.ID, ef["image"]) &&
-			isFiltered(ev.From, ef["image"])) || isFiltered(ev.ID, ef["container"]) {
+	sendEvent := func(ev eventtypes.Message) error {
+		if !filter.Include(ev) {
 			return nil
 		}
-
+		if version.LessThan("1.21") {
+			// Older clients only know about container and image events
+			// in the format of a JSONMessage.
+			if ev.Type != eventtypes.ContainerEventType && ev.Type != eventtypes.ImageEventType {
+				return nil
+			}
+			return enc.Encode(&jsonmessage.JSONMessage{
+				Status: ev.Status,
+				ID:     ev.ID,
+				From:   ev.From,
+				Time:   ev.Time,
+			})
+		}
 		return enc.Encode(ev)
 	}
 
-	current, l := es.Subscribe()
+	var (
+		current []eventtypes.Message
+		l       chan interface{}
+	)
 	if since == -1 {
-		current = nil
+		_, l = es.Subscribe()
+	} else {
+		current, l, err = es.SubscribeRange(since, until)
+		if err != nil {
+			return err
+		}
 	}
 	defer es.Evict(l)
 	for _, ev := range current {
-		if ev.Time < since {
-			continue
-		}
 		if err := sendEvent(ev); err != nil {
 			return err
 		}
@@ -157,7 +140,7 @@ func (s *Server) getEvents(version version.Version, w http.ResponseWriter, r *ht
 	for {
 		select {
 		case ev := <-l:
-			jev, ok := ev.(*jsonmessage.JSONMessage)
+			jev, ok := ev.(eventtypes.Message)
 			if !ok {
 				continue
 			}
@@ -314,6 +314,15 @@ func (s *Server) postBuild(version version.Version, w http.ResponseWriter, r *ht
 		buildConfig.Ulimits = buildUlimits
 	}
 
+	var buildArgs = map[string]string{}
+	buildArgsJSON := r.FormValue("buildargs")
+	if buildArgsJSON != "" {
+		if err := json.NewDecoder(strings.NewReader(buildArgsJSON)).Decode(&buildArgs); err != nil {
+			return err
+		}
+		buildConfig.BuildArgs = buildArgs
+	}
+
 	// Job cancellation. Note: not all job types support this.
 	if closeNotifier, ok := w.(http.CloseNotifier); ok {
 		finished := make(chan struct{})
@@ -383,7 +392,8 @@ func (s *Server) postImagesTag(version version.Version, w http.ResponseWriter, r
 	if err := s.daemon.Repositories().Tag(repo, tag, name, force); err != nil {
 		return err
 	}
-	s.daemon.EventsService.Log("tag", utils.ImageReference(repo, tag), "")
+	ref := utils.ImageReference(repo, tag)
+	s.daemon.LogImageEvent(ref, ref, "tag")
 	w.WriteHeader(http.StatusCreated)
 	return nil
 }
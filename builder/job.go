@@ -40,6 +40,7 @@ var validCommitCommands = map[string]bool{
 	"expose":     true,
 	"label":      true,
 	"onbuild":    true,
+	"stopsignal": true,
 	"user":       true,
 	"volume":     true,
 	"workdir":    true,
@@ -65,6 +66,7 @@ type Config struct {
 	CgroupParent   string
 	Ulimits        []*ulimit.Ulimit
 	AuthConfigs    map[string]cliconfig.AuthConfig
+	BuildArgs      map[string]string
 
 	Stdout  io.Writer
 	Context io.ReadCloser
@@ -92,6 +94,7 @@ func (b *Config) WaitCancelled() <-chan struct{} {
 func NewBuildConfig() *Config {
 	return &Config{
 		AuthConfigs: map[string]cliconfig.AuthConfig{},
+		BuildArgs:   map[string]string{},
 		cancelled:   make(chan struct{}),
 	}
 }
@@ -208,6 +211,7 @@ func Build(d *daemon.Daemon, buildConfig *Config) error {
 		memory:          buildConfig.Memory,
 		memorySwap:      buildConfig.MemorySwap,
 		ulimits:         buildConfig.Ulimits,
+		BuildArgs:       buildConfig.BuildArgs,
 		cancelled:       buildConfig.WaitCancelled(),
 		id:              stringid.GenerateRandomID(),
 	}
@@ -16,7 +16,7 @@
 
 function __fish_docker_no_subcommand --description 'Test if docker has yet to be given the subcommand'
     for i in (commandline -opc)
-        if contains -- $i attach build commit cp create diff events exec export history images import info inspect kill load login logout logs pause port ps pull push rename restart rm rmi run save search start stop tag top unpause version wait stats
+        if contains -- $i attach build checkpoint commit cp create diff events exec export history images import info inspect kill load login logout logs pause port ps pull push rename restart restore rm rmi run save search start stop tag top unpause update version wait stats
             return 1
         end
     end
@@ -46,6 +46,7 @@ end
 complete -c docker -f -n '__fish_docker_no_subcommand' -l api-cors-header -d "Set CORS headers in the remote API. Default is cors disabled"
 complete -c docker -f -n '__fish_docker_no_subcommand' -s b -l bridge -d 'Attach containers to a pre-existing network bridge'
 complete -c docker -f -n '__fish_docker_no_subcommand' -l bip -d "Use this CIDR notation address for the network bridge's IP, not compatible with -b"
+complete -c docker -f -n '__fish_docker_no_subcommand' -l config-file -d 'Daemon configuration file'
 complete -c docker -f -n '__fish_docker_no_subcommand' -s D -l debug -d 'Enable debug mode'
 complete -c docker -f -n '__fish_docker_no_subcommand' -s d -l daemon -d 'Enable daemon mode'
 complete -c docker -f -n '__fish_docker_no_subcommand' -l dns -d 'Force Docker to use specific DNS servers'
@@ -67,6 +68,7 @@ complete -c docker -f -n '__fish_docker_no_subcommand' -l iptables -d "Enable Do
 complete -c docker -f -n '__fish_docker_no_subcommand' -l ipv6 -d 'Enable IPv6 networking'
 complete -c docker -f -n '__fish_docker_no_subcommand' -s l -l log-level -d 'Set the logging level (debug, info, warn, error, fatal)'
 complete -c docker -f -n '__fish_docker_no_subcommand' -l label -d 'Set key=value labels to the daemon (displayed in `docker info`)'
+complete -c docker -f -n '__fish_docker_no_subcommand' -l live-restore -d 'Keep containers running when the daemon is stopped'
 complete -c docker -f -n '__fish_docker_no_subcommand' -l mtu -d 'Set the containers network MTU'
 complete -c docker -f -n '__fish_docker_no_subcommand' -s p -l pidfile -d 'Path to use for daemon PID file'
 complete -c docker -f -n '__fish_docker_no_subcommand' -l registry-mirror -d 'Specify a preferred Docker registry mirror'
@@ -99,6 +101,18 @@ complete -c docker -A -f -n '__fish_seen_subcommand_from build' -s q -l quiet -d
 complete -c docker -A -f -n '__fish_seen_subcommand_from build' -l rm -d 'Remove intermediate containers after a successful build'
 complete -c docker -A -f -n '__fish_seen_subcommand_from build' -s t -l tag -d 'Repository name (and optionally a tag) to be applied to the resulting image in case of success'
 
+# checkpoint
+complete -c docker -f -n '__fish_docker_no_subcommand' -a checkpoint -d 'Checkpoint one or more running containers'
+complete -c docker -A -f -n '__fish_seen_subcommand_from checkpoint' -l allow-ext-unix -d 'Allow checkpointing external unix connections'
+complete -c docker -A -f -n '__fish_seen_subcommand_from checkpoint' -l allow-file-locks -d 'Allow checkpointing file locks'
+complete -c docker -A -f -n '__fish_seen_subcommand_from checkpoint' -l allow-shell -d 'Allow checkpointing shell jobs'
+complete -c docker -A -f -n '__fish_seen_subcommand_from checkpoint' -l allow-tcp -d 'Allow checkpointing tcp connections'
+complete -c docker -A -f -n '__fish_seen_subcommand_from checkpoint' -l help -d 'Print usage'
+complete -c docker -A -f -n '__fish_seen_subcommand_from checkpoint' -l image-dir -d 'Directory for storing checkpoint image files'
+complete -c docker -A -f -n '__fish_seen_subcommand_from checkpoint' -l leave-running -d 'Leave the container running after checkpoint'
+complete -c docker -A -f -n '__fish_seen_subcommand_from checkpoint' -l work-dir -d 'Directory for storing log file'
+complete -c docker -A -f -n '__fish_seen_subcommand_from checkpoint' -a '(__fish_print_docker_containers running)' -d "Container"
+
 # commit
 complete -c docker -f -n '__fish_docker_no_subcommand' -a commit -d "Create a new image from a container's changes"
 complete -c docker -A -f -n '__fish_seen_subcommand_from commit' -s a -l author -d 'Author (e.g., "John Hannibal Smith <hannibal@a-team.com>")'
@@ -121,6 +135,10 @@ complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l cap-drop -d
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l cidfile -d 'Write the container ID to the file'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l cpuset -d 'CPUs in which to allow execution (0-3, 0,1)'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l device -d 'Add a host device to the container (e.g. --device=/dev/sdc:/dev/xvdc:rwm)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l device-read-bps -d 'Limit read rate (bytes per second) from a device (e.g. --device-read-bps=/dev/sda:1mb)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l device-read-iops -d 'Limit read rate (IO per second) from a device (e.g. --device-read-iops=/dev/sda:1000)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l device-write-bps -d 'Limit write rate (bytes per second) to a device (e.g. --device-write-bps=/dev/sda:1mb)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l device-write-iops -d 'Limit write rate (IO per second) to a device (e.g. --device-write-iops=/dev/sda:1000)'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l dns -d 'Set custom DNS servers'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l dns-search -d "Set custom DNS search domains (Use --dns-search=. if you don't wish to set the search domain)"
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -s e -l env -d 'Set environment variables'
@@ -136,6 +154,7 @@ complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l link -d 'Add
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l lxc-conf -d '(lxc exec-driver only) Add custom lxc options --lxc-conf="lxc.cgroup.cpuset.cpus = 0,1"'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -s m -l memory -d 'Memory limit (format: <number><optional unit>, where unit = b, k, m or g)'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l mac-address -d 'Container MAC address (e.g. 92:d0:c6:0a:29:33)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l pids-limit -d 'Tune container pids limit (set -1 for unlimited)'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l memory-swap -d "Total memory usage (memory + swap), set '-1' to disable swap (format: <number><optional unit>, where unit = b, k, m or g)"
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l name -d 'Assign a name to the container'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l net -d 'Set the Network mode for the container'
@@ -144,7 +163,9 @@ complete -c docker -A -f -n '__fish_seen_subcommand_from create' -s p -l publish
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l pid -d 'Default is to create a private PID namespace for the container'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l privileged -d 'Give extended privileges to this container'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l read-only -d "Mount the container's root filesystem as read only"
-complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l restart -d 'Restart policy to apply when a container exits (no, on-failure[:max-retry], always)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l restart -d 'Restart policy to apply when a container exits (no, on-failure[:max-retry], always, unless-stopped)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l restart-max-backoff -d 'Maximum delay between two restarts of the container'
+complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l restart-reset-window -d 'Running time after which the delay between restarts is reset'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -l security-opt -d 'Security Options'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -s t -l tty -d 'Allocate a pseudo-TTY'
 complete -c docker -A -f -n '__fish_seen_subcommand_from create' -s u -l user -d 'Username or UID'
@@ -276,6 +297,18 @@ complete -c docker -A -f -n '__fish_seen_subcommand_from push' -a '(__fish_print
 # rename
 complete -c docker -f -n '__fish_docker_no_subcommand' -a rename -d 'Rename an existing container'
 
+# restore
+complete -c docker -f -n '__fish_docker_no_subcommand' -a restore -d 'Restore one or more checkpointed containers'
+complete -c docker -A -f -n '__fish_seen_subcommand_from restore' -l allow-ext-unix -d 'Allow restoring external unix connections'
+complete -c docker -A -f -n '__fish_seen_subcommand_from restore' -l allow-file-locks -d 'Allow restoring file locks'
+complete -c docker -A -f -n '__fish_seen_subcommand_from restore' -l allow-shell -d 'Allow restoring shell jobs'
+complete -c docker -A -f -n '__fish_seen_subcommand_from restore' -l allow-tcp -d 'Allow restoring tcp connections'
+complete -c docker -A -f -n '__fish_seen_subcommand_from restore' -l force -d 'Restore a container that was not checkpointed'
+complete -c docker -A -f -n '__fish_seen_subcommand_from restore' -l help -d 'Print usage'
+complete -c docker -A -f -n '__fish_seen_subcommand_from restore' -l image-dir -d 'Directory to restore the container from'
+complete -c docker -A -f -n '__fish_seen_subcommand_from restore' -l work-dir -d 'Directory for restore log'
+complete -c docker -A -f -n '__fish_seen_subcommand_from restore' -a '(__fish_print_docker_containers all)' -d "Container"
+
 # restart
 complete -c docker -f -n '__fish_docker_no_subcommand' -a restart -d 'Restart a running container'
 complete -c docker -A -f -n '__fish_seen_subcommand_from restart' -l help -d 'Print usage'
@@ -308,6 +341,10 @@ complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l cidfile -d 'Wri
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l cpuset -d 'CPUs in which to allow execution (0-3, 0,1)'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -s d -l detach -d 'Detached mode: run the container in the background and print the new container ID'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l device -d 'Add a host device to the container (e.g. --device=/dev/sdc:/dev/xvdc:rwm)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l device-read-bps -d 'Limit read rate (bytes per second) from a device (e.g. --device-read-bps=/dev/sda:1mb)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l device-read-iops -d 'Limit read rate (IO per second) from a device (e.g. --device-read-iops=/dev/sda:1000)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l device-write-bps -d 'Limit write rate (bytes per second) to a device (e.g. --device-write-bps=/dev/sda:1mb)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l device-write-iops -d 'Limit write rate (IO per second) to a device (e.g. --device-write-iops=/dev/sda:1000)'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l dns -d 'Set custom DNS servers'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l dns-search -d "Set custom DNS search domains (Use --dns-search=. if you don't wish to set the search domain)"
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -s e -l env -d 'Set environment variables'
@@ -323,6 +360,7 @@ complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l link -d 'Add li
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l lxc-conf -d '(lxc exec-driver only) Add custom lxc options --lxc-conf="lxc.cgroup.cpuset.cpus = 0,1"'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -s m -l memory -d 'Memory limit (format: <number><optional unit>, where unit = b, k, m or g)'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l mac-address -d 'Container MAC address (e.g. 92:d0:c6:0a:29:33)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l pids-limit -d 'Tune container pids limit (set -1 for unlimited)'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l memory-swap -d "Total memory usage (memory + swap), set '-1' to disable swap (format: <number><optional unit>, where unit = b, k, m or g)"
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l name -d 'Assign a name to the container'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l net -d 'Set the Network mode for the container'
@@ -331,7 +369,9 @@ complete -c docker -A -f -n '__fish_seen_subcommand_from run' -s p -l publish -d
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l pid -d 'Default is to create a private PID namespace for the container'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l privileged -d 'Give extended privileges to this container'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l read-only -d "Mount the container's root filesystem as read only"
-complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l restart -d 'Restart policy to apply when a container exits (no, on-failure[:max-retry], always)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l restart -d 'Restart policy to apply when a container exits (no, on-failure[:max-retry], always, unless-stopped)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l restart-max-backoff -d 'Maximum delay between two restarts of the container'
+complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l restart-reset-window -d 'Running time after which the delay between restarts is reset'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l rm -d 'Automatically remove the container when it exits (incompatible with -d)'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l security-opt -d 'Security Options'
 complete -c docker -A -f -n '__fish_seen_subcommand_from run' -l sig-proxy -d 'Proxy received signals to the process (non-TTY mode only). SIGCHLD, SIGSTOP, and SIGKILL are not proxied.'
@@ -388,6 +428,19 @@ complete -c docker -A -f -n '__fish_seen_subcommand_from top' -a '(__fish_print_
 complete -c docker -f -n '__fish_docker_no_subcommand' -a unpause -d 'Unpause a paused container'
 complete -c docker -A -f -n '__fish_seen_subcommand_from unpause' -a '(__fish_print_docker_containers running)' -d "Container"
 
+# update
+complete -c docker -f -n '__fish_docker_no_subcommand' -a update -d 'Update resources of one or more containers'
+complete -c docker -A -f -n '__fish_seen_subcommand_from update' -l blkio-weight -d 'Block IO (relative weight), between 10 and 1000'
+complete -c docker -A -f -n '__fish_seen_subcommand_from update' -l cpu-period -d 'Limit CPU CFS (Completely Fair Scheduler) period'
+complete -c docker -A -f -n '__fish_seen_subcommand_from update' -l cpu-quota -d 'Limit CPU CFS (Completely Fair Scheduler) quota'
+complete -c docker -A -f -n '__fish_seen_subcommand_from update' -s c -l cpu-shares -d 'CPU shares (relative weight)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from update' -l cpuset-cpus -d 'CPUs in which to allow execution (0-3, 0,1)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from update' -l cpuset-mems -d 'MEMs in which to allow execution (0-3, 0,1)'
+complete -c docker -A -f -n '__fish_seen_subcommand_from update' -l help -d 'Print usage'
+complete -c docker -A -f -n '__fish_seen_subcommand_from update' -s m -l memory -d 'Memory limit'
+complete -c docker -A -f -n '__fish_seen_subcommand_from update' -l memory-swap -d "Total memory (memory + swap), '-1' to disable swap"
+complete -c docker -A -f -n '__fish_seen_subcommand_from update' -a '(__fish_print_docker_containers all)' -d "Container"
+
 # version
 complete -c docker -f -n '__fish_docker_no_subcommand' -a version -d 'Show the Docker version information'
 
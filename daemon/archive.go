@@ -205,7 +205,10 @@ func (container *Container) ArchivePath(path string) (content io.ReadCloser, sta
 	// also catches the case when the root directory of the container is
 	// requested: we want the archive entries to start with "/" and not the
 	// container ID.
-	data, err := archive.TarResourceRebase(resolvedPath, filepath.Base(absPath))
+	opts := archive.TarResourceRebaseOpts(resolvedPath, filepath.Base(absPath))
+	opts.UIDMaps, opts.GIDMaps = container.daemon.GetUIDGIDMaps()
+
+	data, err := archive.TarResourceRebaseWithOptions(resolvedPath, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -291,11 +294,15 @@ func (container *Container) ExtractToDir(path string, noOverwriteDirNonDir bool,
 		return ErrContainerRootfsReadonly
 	}
 
+	uidMaps, gidMaps := container.daemon.GetUIDGIDMaps()
+	rootUID, rootGID := container.daemon.GetRemappedUIDGID()
 	options := &archive.TarOptions{
 		ChownOpts: &archive.TarChownOptions{
-			UID: 0, GID: 0, // TODO: use config.User? Remap to userns root?
+			UID: rootUID, GID: rootGID, // TODO: use config.User?
 		},
 		NoOverwriteDirNonDir: noOverwriteDirNonDir,
+		UIDMaps:              uidMaps,
+		GIDMaps:              gidMaps,
 	}
 
 	if err := chrootarchive.Untar(content, resolvedPath, options); err != nil {
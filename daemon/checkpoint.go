@@ -0,0 +1,39 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/runconfig"
+)
+
+// ContainerCheckpoint saves the state of the processes of a running
+// container to disk, so that they can be restored later with
+// ContainerRestore.
+func (daemon *Daemon) ContainerCheckpoint(name string, opts *runconfig.CriuConfig) error {
+	container, err := daemon.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := container.Checkpoint(opts); err != nil {
+		return fmt.Errorf("Cannot checkpoint container %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// ContainerRestore starts a container by restoring its processes from a
+// checkpoint. forceRestore allows restoring a container that was not
+// stopped by a checkpoint, for instance from the images of another host.
+func (daemon *Daemon) ContainerRestore(name string, opts *runconfig.CriuConfig, forceRestore bool) error {
+	container, err := daemon.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := container.Restore(opts, forceRestore); err != nil {
+		return fmt.Errorf("Cannot restore container %s: %s", name, err)
+	}
+
+	return nil
+}
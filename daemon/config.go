@@ -16,7 +16,9 @@ const (
 type CommonConfig struct {
 	AutoRestart    bool
 	Bridge         bridgeConfig // Bridge holds bridge network specific configuration.
+	ConfigFile     string
 	Context        map[string][]string
+	Debug          bool
 	DisableBridge  bool
 	Dns            []string
 	DnsSearch      []string
@@ -26,6 +28,7 @@ type CommonConfig struct {
 	GraphDriver    string
 	GraphOptions   []string
 	Labels         []string
+	LiveRestore    bool
 	LogConfig      runconfig.LogConfig
 	Mtu            int
 	Pidfile        string
@@ -54,5 +57,9 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	cmd.Var(opts.NewListOptsRef(&config.DnsSearch, opts.ValidateDNSSearch), []string{"-dns-search"}, usageFn("DNS search domains to use"))
 	cmd.Var(opts.NewListOptsRef(&config.Labels, opts.ValidateLabel), []string{"-label"}, usageFn("Set key=value labels to the daemon"))
 	cmd.StringVar(&config.LogConfig.Type, []string{"-log-driver"}, "json-file", usageFn("Default driver for container logs"))
+	if config.LogConfig.Config == nil {
+		config.LogConfig.Config = make(map[string]string)
+	}
 	cmd.Var(opts.NewMapOpts(config.LogConfig.Config, nil), []string{"-log-opt"}, usageFn("Set log driver options"))
+	cmd.StringVar(&config.ConfigFile, []string{"-config-file"}, defaultConfigFile, usageFn("Daemon configuration file"))
 }
@@ -0,0 +1,154 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	flag "github.com/docker/docker/pkg/mflag"
+)
+
+// ReloadableOptions are the options of the configuration file that Reload
+// applies to a running daemon. The other options need a restart.
+var ReloadableOptions = []string{"debug", "insecure-registry", "label", "log-opt", "registry-mirror"}
+
+// ReadConfigFile reads the daemon configuration file at path. The file holds
+// a JSON object whose keys are the long names of the daemon flags. Lists give
+// a flag several times and objects give key=value pairs, so
+//
+//	{"label": ["a=b"], "log-opt": {"max-size": "10m"}, "debug": true}
+//
+// is the same as `--label a=b --log-opt max-size=10m --debug`. The values
+// are returned by option name, in the order they would be given as flags.
+func ReadConfigFile(path string) (map[string][]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var options map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&options); err != nil {
+		return nil, fmt.Errorf("Error reading the configuration file %s: %v", path, err)
+	}
+
+	values := make(map[string][]string)
+	for key, value := range options {
+		var err error
+		switch v := value.(type) {
+		case []interface{}:
+			for _, e := range v {
+				var s string
+				if s, err = configValue(e); err != nil {
+					break
+				}
+				values[key] = append(values[key], s)
+			}
+		case map[string]interface{}:
+			names := make([]string, 0, len(v))
+			for name := range v {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				var s string
+				if s, err = configValue(v[name]); err != nil {
+					break
+				}
+				values[key] = append(values[key], name+"="+s)
+			}
+		default:
+			var s string
+			if s, err = configValue(v); err == nil {
+				values[key] = []string{s}
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for %s in the configuration file %s: %v", key, path, err)
+		}
+	}
+	return values, nil
+}
+
+func configValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return fmt.Sprint(v), nil
+	case json.Number:
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("unexpected %v", v)
+}
+
+// SetConfigFlags sets the flags named by the keys of values, read from the
+// configuration file. Each flag is looked up in flags. An option is rejected
+// when no flag has its name, or when its flag was given in one of the
+// commandLine flag sets: the configuration file does not override flags.
+func SetConfigFlags(values map[string][]string, flags, commandLine []*flag.FlagSet) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var unknown, conflicts []string
+	for _, key := range keys {
+		f := lookupFlag(flags, key)
+		if f == nil || key == "config-file" {
+			unknown = append(unknown, key)
+		} else if isSetFlag(commandLine, f) {
+			conflicts = append(conflicts, key)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("Unknown options in the configuration file: %s", strings.Join(unknown, ", "))
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("The following options are given both as flags and in the configuration file: %s", strings.Join(conflicts, ", "))
+	}
+
+	for _, key := range keys {
+		f := lookupFlag(flags, key)
+		for _, v := range values[key] {
+			// Value.Set is used instead of FlagSet.Set so the flag is not
+			// reported as given on the command line.
+			if err := f.Value.Set(v); err != nil {
+				return fmt.Errorf("Invalid value %q for %s in the configuration file: %v", v, key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// IsSetOnCommandLine returns whether the flag of the option name, in any of
+// its forms, was given in one of the commandLine flag sets.
+func IsSetOnCommandLine(name string, commandLine []*flag.FlagSet) bool {
+	f := lookupFlag(commandLine, name)
+	return f != nil && isSetFlag(commandLine, f)
+}
+
+func lookupFlag(flags []*flag.FlagSet, name string) *flag.Flag {
+	for _, fs := range flags {
+		if f := fs.Lookup("-" + name); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+func isSetFlag(flags []*flag.FlagSet, f *flag.Flag) bool {
+	for _, fs := range flags {
+		for _, name := range f.Names {
+			if fs.IsSet(strings.TrimPrefix(name, "#")) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	flag "github.com/docker/docker/pkg/mflag"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "daemon-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func newConfigFlags() (*Config, *flag.FlagSet) {
+	config := &Config{}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	config.InstallFlags(flags, func(string) string { return "" })
+	flags.BoolVar(&config.Debug, []string{"D", "-debug"}, false, "")
+	return config, flags
+}
+
+func TestReadConfigFile(t *testing.T) {
+	path := writeConfigFile(t, `{
+		"label": ["a=b", "c=d"],
+		"log-opt": {"max-size": "10m", "max-file": 3},
+		"debug": true,
+		"mtu": 1400
+	}`)
+	defer os.Remove(path)
+
+	values, err := ReadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"label":   "a=b c=d",
+		"log-opt": "max-file=3 max-size=10m",
+		"debug":   "true",
+		"mtu":     "1400",
+	}
+	if len(values) != len(expected) {
+		t.Fatalf("Expected %d options, got %v", len(expected), values)
+	}
+	for key, v := range expected {
+		if actual := strings.Join(values[key], " "); actual != v {
+			t.Fatalf("Expected %s to be %q, got %q", key, v, actual)
+		}
+	}
+
+	for _, content := range []string{`["label"]`, `{"label": null}`, `{"label": [["a=b"]]}`, `{"label"`} {
+		path := writeConfigFile(t, content)
+		defer os.Remove(path)
+		if _, err := ReadConfigFile(path); err == nil {
+			t.Fatalf("Expected an error reading %s", content)
+		}
+	}
+}
+
+func TestSetConfigFlags(t *testing.T) {
+	config, flags := newConfigFlags()
+	commandLine := flag.NewFlagSet("command-line", flag.ContinueOnError)
+	commandLine.BoolVar(new(bool), []string{"D", "-debug"}, false, "")
+	if err := commandLine.Parse([]string{"-D"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := SetConfigFlags(map[string][]string{"debug": {"true"}, "label": {"a=b"}}, []*flag.FlagSet{flags}, []*flag.FlagSet{commandLine})
+	if err == nil || !strings.Contains(err.Error(), "debug") || strings.Contains(err.Error(), "label") {
+		t.Fatalf("Expected a conflict on debug, got %v", err)
+	}
+	if err := SetConfigFlags(map[string][]string{"nonexistent": {"true"}}, []*flag.FlagSet{flags}, nil); err == nil {
+		t.Fatal("Expected an error for an unknown option")
+	}
+	if err := SetConfigFlags(map[string][]string{"label": {"invalid"}}, []*flag.FlagSet{flags}, nil); err == nil {
+		t.Fatal("Expected an error for an invalid label")
+	}
+
+	config, flags = newConfigFlags()
+	values := map[string][]string{
+		"label":   {"a=b", "c=d"},
+		"log-opt": {"max-size=10m"},
+		"mtu":     {"1400"},
+	}
+	if err := SetConfigFlags(values, []*flag.FlagSet{flags}, []*flag.FlagSet{commandLine, flags}); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Labels) != 2 || config.Labels[1] != "c=d" || config.Mtu != 1400 || config.LogConfig.Config["max-size"] != "10m" {
+		t.Fatalf("Unexpected configuration %+v", config.CommonConfig)
+	}
+	if flags.IsSet("-label") || IsSetOnCommandLine("label", []*flag.FlagSet{flags}) {
+		t.Fatal("Expected options of the configuration file not to be reported as flags")
+	}
+	if !IsSetOnCommandLine("debug", []*flag.FlagSet{commandLine}) {
+		t.Fatal("Expected -D to be reported as debug")
+	}
+}
@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"os"
+	"path/filepath"
 
 	flag "github.com/docker/docker/pkg/mflag"
 )
@@ -10,6 +11,8 @@ var (
 	defaultPidFile = os.Getenv("programdata") + string(os.PathSeparator) + "docker.pid"
 	defaultGraph   = os.Getenv("programdata") + string(os.PathSeparator) + "docker"
 	defaultExec    = "windows"
+
+	defaultConfigFile = filepath.Join(os.Getenv("programdata"), "docker", "config", "daemon.json")
 )
 
 // bridgeConfig stores all the bridge driver specific
@@ -8,17 +8,20 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/opencontainers/runc/libcontainer"
 	"github.com/opencontainers/runc/libcontainer/label"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/daemon/execdriver"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/logger/jsonfilelog"
+	"github.com/docker/docker/daemon/logger/localcache"
 	"github.com/docker/docker/daemon/network"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/pkg/archive"
@@ -28,6 +31,7 @@ import (
 	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/pkg/nat"
 	"github.com/docker/docker/pkg/promise"
+	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/docker/pkg/symlink"
 	"github.com/docker/docker/runconfig"
 	"github.com/docker/docker/volume"
@@ -82,6 +86,7 @@ type CommonContainer struct {
 	hostConfig               *runconfig.HostConfig
 	command                  *execdriver.Command
 	monitor                  *containerMonitor
+	restartInProgress        bool // Set while the container is stopped by a restart
 	execCommands             *execStore
 	daemon                   *Daemon
 	// logDriver for closing
@@ -178,13 +183,9 @@ func (container *Container) WriteHostConfig() error {
 	return ioutil.WriteFile(pth, data, 0666)
 }
 
+// LogEvent generates an event for the container with the default attributes.
 func (container *Container) LogEvent(action string) {
-	d := container.daemon
-	d.EventsService.Log(
-		action,
-		container.ID,
-		container.Config.Image,
-	)
+	container.daemon.LogContainerEvent(container, action)
 }
 
 // Evaluates `path` in the scope of the container's basefs, with proper path
@@ -227,14 +228,35 @@ func (container *Container) GetRootResourcePath(path string) (string, error) {
 	return symlink.FollowSymlinkInScope(filepath.Join(container.root, cleanPath), container.root)
 }
 
-func (container *Container) Start() (err error) {
+func (container *Container) Start() error {
 	container.Lock()
 	defer container.Unlock()
 
 	if container.Running {
 		return nil
 	}
+	return container.start(nil)
+}
+
+// Restore starts the container by restoring its processes from a checkpoint.
+// Unless forceRestore is set, the container must have been stopped by a
+// checkpoint.
+func (container *Container) Restore(opts *runconfig.CriuConfig, forceRestore bool) error {
+	container.Lock()
+	defer container.Unlock()
 
+	if container.Running {
+		return fmt.Errorf("Container %s is already running", container.ID)
+	}
+	if !container.Checkpointed && !forceRestore {
+		return fmt.Errorf("Container %s is not checkpointed", container.ID)
+	}
+	return container.start(container.criuOpts(opts))
+}
+
+// start sets up the container and starts its process, or restores it from a
+// checkpoint when restoreOpts is set. The container lock must be held.
+func (container *Container) start(restoreOpts *libcontainer.CriuOpts) (err error) {
 	if container.removalInProgress || container.Dead {
 		return fmt.Errorf("Container is marked for removal and cannot be started.")
 	}
@@ -250,7 +272,14 @@ func (container *Container) Start() (err error) {
 			}
 			container.toDisk()
 			container.cleanup()
-			container.LogEvent("die")
+			attributes := map[string]string{
+				"exitCode": strconv.Itoa(container.ExitCode),
+			}
+			container.daemon.LogContainerEventWithAttributes(container, "die", attributes)
+			if container.hostConfig.AutoRemove {
+				// the container lock is held until start returns
+				go container.daemon.autoRemove(container)
+			}
 		}
 	}()
 
@@ -285,7 +314,7 @@ func (container *Container) Start() (err error) {
 	}
 
 	container.command.Mounts = mounts
-	return container.waitForStart()
+	return container.waitForStart(restoreOpts)
 }
 
 func (container *Container) Run() error {
@@ -384,7 +413,10 @@ func (container *Container) KillSig(sig int) error {
 	if err := container.daemon.Kill(container, sig); err != nil {
 		return err
 	}
-	container.LogEvent("kill")
+	attributes := map[string]string{
+		"signal": strconv.Itoa(sig),
+	}
+	container.daemon.LogContainerEventWithAttributes(container, "kill", attributes)
 	return nil
 }
 
@@ -420,6 +452,30 @@ func (container *Container) Pause() error {
 	return nil
 }
 
+// updateResources replaces the host config of the container, and applies
+// its resources to the container when it is running. The container lock must
+// be held.
+func (container *Container) updateResources(hostConfig *runconfig.HostConfig) error {
+	if container.Running {
+		resources := container.command.Resources
+		old := *resources
+		resources.Memory = hostConfig.Memory
+		resources.MemorySwap = hostConfig.MemorySwap
+		resources.CPUShares = hostConfig.CPUShares
+		resources.CPUPeriod = hostConfig.CPUPeriod
+		resources.CPUQuota = hostConfig.CPUQuota
+		resources.CpusetCpus = hostConfig.CpusetCpus
+		resources.CpusetMems = hostConfig.CpusetMems
+		resources.BlkioWeight = hostConfig.BlkioWeight
+		if err := container.daemon.execDriver.Update(container.command); err != nil {
+			*resources = old
+			return err
+		}
+	}
+	container.hostConfig = hostConfig
+	return container.WriteHostConfig()
+}
+
 func (container *Container) Unpause() error {
 	container.Lock()
 	defer container.Unlock()
@@ -442,6 +498,61 @@ func (container *Container) Unpause() error {
 	return nil
 }
 
+// Checkpoint saves the state of the processes of the container to disk. The
+// container is stopped and marked as checkpointed unless opts.LeaveRunning
+// is set.
+func (container *Container) Checkpoint(opts *runconfig.CriuConfig) error {
+	container.Lock()
+	if !container.Running {
+		container.Unlock()
+		return ErrContainerNotRunning{container.ID}
+	}
+	if container.Paused {
+		container.Unlock()
+		return fmt.Errorf("Container %s is paused. Unpause the container before checkpointing it", container.ID)
+	}
+	if !opts.LeaveRunning && container.hostConfig.AutoRemove {
+		container.Unlock()
+		return fmt.Errorf("Container %s is removed when it exits and can only be checkpointed with --leave-running", container.ID)
+	}
+	if !opts.LeaveRunning {
+		// the processes are stopped by the checkpoint, the restart policy
+		// must not start them again
+		container.monitor.ExitOnNext()
+	}
+	err := container.daemon.Checkpoint(container, container.criuOpts(opts))
+	container.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if !opts.LeaveRunning {
+		container.WaitStop(-1 * time.Second)
+		container.SetCheckpointed()
+	}
+	container.LogEvent("checkpoint")
+	return container.ToDisk()
+}
+
+// criuOpts returns the CRIU options of a checkpoint or a restore. The
+// checkpoint is stored under the root of the container unless another
+// directory is given.
+func (container *Container) criuOpts(opts *runconfig.CriuConfig) *libcontainer.CriuOpts {
+	criuOpts := &libcontainer.CriuOpts{
+		ImagesDirectory:         opts.ImagesDirectory,
+		WorkDirectory:           opts.WorkDirectory,
+		LeaveRunning:            opts.LeaveRunning,
+		TcpEstablished:          opts.TCPEstablished,
+		ExternalUnixConnections: opts.ExternalUnixConnections,
+		ShellJob:                opts.ShellJob,
+		FileLocks:               opts.FileLocks,
+	}
+	if criuOpts.ImagesDirectory == "" {
+		criuOpts.ImagesDirectory = filepath.Join(container.root, "checkpoint")
+	}
+	return criuOpts
+}
+
 func (container *Container) Kill() error {
 	if !container.IsRunning() {
 		return ErrContainerNotRunning{container.ID}
@@ -482,9 +593,10 @@ func (container *Container) Stop(seconds int) error {
 		return nil
 	}
 
-	// 1. Send a SIGTERM
-	if err := container.killPossiblyDeadProcess(15); err != nil {
-		logrus.Infof("Failed to send SIGTERM to the process, force killing")
+	// 1. Send the stop signal (SIGTERM unless the container configures another one)
+	stopSignal := container.StopSignal()
+	if err := container.killPossiblyDeadProcess(stopSignal); err != nil {
+		logrus.Infof("Failed to send signal %d to the process, force killing", stopSignal)
 		if err := container.killPossiblyDeadProcess(9); err != nil {
 			return err
 		}
@@ -492,7 +604,7 @@ func (container *Container) Stop(seconds int) error {
 
 	// 2. Wait for the process to exit on its own
 	if _, err := container.WaitStop(time.Duration(seconds) * time.Second); err != nil {
-		logrus.Infof("Container %v failed to exit within %d seconds of SIGTERM - using the force", container.ID, seconds)
+		logrus.Infof("Container %v failed to exit within %d seconds of signal %d - using the force", container.ID, seconds, stopSignal)
 		// 3. If it doesn't, then send SIGKILL
 		if err := container.Kill(); err != nil {
 			container.WaitStop(-1 * time.Second)
@@ -504,6 +616,19 @@ func (container *Container) Stop(seconds int) error {
 	return nil
 }
 
+// StopSignal returns the signal used to stop the container.
+func (container *Container) StopSignal() int {
+	var stopSignal syscall.Signal
+	if container.Config.StopSignal != "" {
+		stopSignal, _ = signal.ParseSignal(container.Config.StopSignal)
+	}
+
+	if int(stopSignal) == 0 {
+		stopSignal, _ = signal.ParseSignal(signal.DefaultStopSignal)
+	}
+	return int(stopSignal)
+}
+
 func (container *Container) Restart(seconds int) error {
 	// Avoid unnecessarily unmounting and then directly mounting
 	// the container when the container stops and then starts
@@ -512,6 +637,17 @@ func (container *Container) Restart(seconds int) error {
 		defer container.Unmount()
 	}
 
+	// a container created with --rm must not be removed when it is stopped
+	// to be restarted
+	container.Lock()
+	container.restartInProgress = true
+	container.Unlock()
+	defer func() {
+		container.Lock()
+		container.restartInProgress = false
+		container.Unlock()
+	}()
+
 	if err := container.Stop(seconds); err != nil {
 		return err
 	}
@@ -540,7 +676,12 @@ func (container *Container) Export() (archive.Archive, error) {
 		return nil, err
 	}
 
-	archive, err := archive.Tar(container.basefs, archive.Uncompressed)
+	uidMaps, gidMaps := container.daemon.GetUIDGIDMaps()
+	archive, err := archive.TarWithOptions(container.basefs, &archive.TarOptions{
+		Compression: archive.Uncompressed,
+		UIDMaps:     uidMaps,
+		GIDMaps:     gidMaps,
+	})
 	if err != nil {
 		container.Unmount()
 		return nil, err
@@ -646,9 +787,12 @@ func (container *Container) Copy(resource string) (rc io.ReadCloser, err error)
 		filter = []string{filepath.Base(basePath)}
 		basePath = filepath.Dir(basePath)
 	}
+	uidMaps, gidMaps := container.daemon.GetUIDGIDMaps()
 	archive, err := archive.TarWithOptions(basePath, &archive.TarOptions{
 		Compression:  archive.Uncompressed,
 		IncludeFiles: filter,
+		UIDMaps:      uidMaps,
+		GIDMaps:      gidMaps,
 	})
 	if err != nil {
 		return nil, err
@@ -686,7 +830,7 @@ func (container *Container) getLogConfig() runconfig.LogConfig {
 		return cfg
 	}
 	// Use daemon's default log config for containers
-	return container.daemon.defaultLogConfig
+	return container.daemon.getDefaultLogConfig()
 }
 
 func (container *Container) getLogger() (logger.Logger, error) {
@@ -694,7 +838,7 @@ func (container *Container) getLogger() (logger.Logger, error) {
 		return container.logDriver, nil
 	}
 	cfg := container.getLogConfig()
-	if err := logger.ValidateLogOpts(cfg.Type, cfg.Config); err != nil {
+	if err := localcache.ValidateLogOpts(cfg.Type, cfg.Config); err != nil {
 		return nil, err
 	}
 	c, err := logger.GetLogDriver(cfg.Type)
@@ -702,7 +846,7 @@ func (container *Container) getLogger() (logger.Logger, error) {
 		return nil, fmt.Errorf("Failed to get logging factory: %v", err)
 	}
 	ctx := logger.Context{
-		Config:              cfg.Config,
+		Config:              localcache.DriverOpts(cfg.Config),
 		ContainerID:         container.ID,
 		ContainerName:       container.Name,
 		ContainerEntrypoint: container.Path,
@@ -710,6 +854,7 @@ func (container *Container) getLogger() (logger.Logger, error) {
 		ContainerImageID:    container.ImageID,
 		ContainerImageName:  container.Config.Image,
 		ContainerCreated:    container.Created,
+		ContainerLabels:     container.Config.Labels,
 	}
 
 	// Set logging file for "json-logger"
@@ -719,7 +864,27 @@ func (container *Container) getLogger() (logger.Logger, error) {
 			return nil, err
 		}
 	}
-	return c(ctx)
+	l, err := c(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep a local copy of the logs if the driver cannot read them back
+	if localcache.Enabled(cfg.Config) {
+		cachePath, err := container.GetRootResourcePath(fmt.Sprintf("%s-cache.log", container.ID))
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		ctx.Config = cfg.Config
+		cl, err := localcache.New(l, ctx, cachePath)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		l = cl
+	}
+	return l, nil
 }
 
 func (container *Container) startLogging() error {
@@ -746,14 +911,39 @@ func (container *Container) startLogging() error {
 	return nil
 }
 
-func (container *Container) waitForStart() error {
+func (container *Container) waitForStart(restoreOpts *libcontainer.CriuOpts) error {
+	container.monitor = newContainerMonitor(container, container.hostConfig.RestartPolicy)
+
+	start := container.monitor.Start
+	if restoreOpts != nil {
+		start = func() error { return container.monitor.Restore(restoreOpts) }
+	}
+	return container.waitForMonitor(start)
+}
+
+// reattach attaches the daemon to the process of a container kept running
+// by live restore while the daemon was stopped.
+func (container *Container) reattach() error {
+	container.Lock()
+	defer container.Unlock()
+
+	if err := container.Mount(); err != nil {
+		return err
+	}
+	if err := populateCommand(container, container.createDaemonEnvironment(nil)); err != nil {
+		return err
+	}
 	container.monitor = newContainerMonitor(container, container.hostConfig.RestartPolicy)
+	return container.waitForMonitor(container.monitor.Reattach)
+}
 
+// waitForMonitor runs the monitor of the container with start.
+func (container *Container) waitForMonitor(start func() error) error {
 	// block until we either receive an error from the initial start of the container's
 	// process or until the process is running in the container
 	select {
 	case <-container.monitor.startSignal:
-	case err := <-promise.Go(container.monitor.Start):
+	case err := <-promise.Go(start):
 		return err
 	}
 
@@ -784,7 +974,7 @@ func (c *Container) LogDriverType() string {
 	c.Lock()
 	defer c.Unlock()
 	if c.hostConfig.LogConfig.Type == "" {
-		return c.daemon.defaultLogConfig.Type
+		return c.daemon.getDefaultLogConfig().Type
 	}
 	return c.hostConfig.LogConfig.Type
 }
@@ -866,6 +1056,10 @@ func (c *Container) AttachWithLogs(stdin io.ReadCloser, stdout, stderr io.Writer
 		if err != nil {
 			return err
 		}
+		if logDriver != c.logDriver {
+			// the logger was created only to read the logs
+			defer logDriver.Close()
+		}
 		cLog, ok := logDriver.(logger.LogReader)
 		if !ok {
 			return logger.ErrReadLogsNotSupported
@@ -1063,9 +1257,19 @@ func copyEscapable(dst io.Writer, src io.ReadCloser) (written int64, err error)
 
 func (container *Container) shouldRestart() bool {
 	return container.hostConfig.RestartPolicy.Name == "always" ||
+		(container.hostConfig.RestartPolicy.Name == "unless-stopped" && !container.HasBeenManuallyStopped) ||
 		(container.hostConfig.RestartPolicy.Name == "on-failure" && container.ExitCode != 0)
 }
 
+// shouldAutoRemove returns whether the container was created with --rm and
+// has stopped for good.
+func (container *Container) shouldAutoRemove() bool {
+	container.Lock()
+	defer container.Unlock()
+	return container.hostConfig.AutoRemove && !container.Running &&
+		!container.restartInProgress && !container.removalInProgress && !container.Dead
+}
+
 func (container *Container) mountVolumes() error {
 	mounts, err := container.setupMounts()
 	if err != nil {
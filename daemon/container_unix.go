@@ -19,15 +19,17 @@ import (
 	"github.com/docker/docker/daemon/links"
 	"github.com/docker/docker/daemon/network"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/blkiodev"
 	"github.com/docker/docker/pkg/directory"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/nat"
 	"github.com/docker/docker/pkg/stringid"
-	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/pkg/ulimit"
 	"github.com/docker/docker/runconfig"
 	"github.com/docker/docker/utils"
 	"github.com/docker/docker/volume"
+	"github.com/docker/docker/volume/store"
 	"github.com/docker/libnetwork"
 	"github.com/docker/libnetwork/netlabel"
 	"github.com/docker/libnetwork/options"
@@ -45,6 +47,7 @@ type Container struct {
 	// Fields below here are platform specific.
 	activeLinks     map[string]*links.Link
 	AppArmorProfile string
+	SeccompProfile  string
 	HostnamePath    string
 	HostsPath       string
 	MountPoints     map[string]*mountPoint
@@ -168,6 +171,20 @@ func getDevicesFromPath(deviceMapping runconfig.DeviceMapping) (devs []*configs.
 	return devs, fmt.Errorf("error gathering device information while adding custom device %q: %s", deviceMapping.PathOnHost, err)
 }
 
+// getBlkioThrottleDevices converts the throttle devices of the host config
+// into the "major:minor rate" form of the blkio cgroup.
+func getBlkioThrottleDevices(devs []*blkiodev.ThrottleDevice) ([]string, error) {
+	var throttleDevices []string
+	for _, d := range devs {
+		device, err := devices.DeviceFromPath(d.Path, "rwm")
+		if err != nil {
+			return nil, fmt.Errorf("error gathering device information for %q: %s", d.Path, err)
+		}
+		throttleDevices = append(throttleDevices, fmt.Sprintf("%d:%d %d", device.Major, device.Minor, d.Rate))
+	}
+	return throttleDevices, nil
+}
+
 func populateCommand(c *Container, env []string) error {
 	var en *execdriver.Network
 	if !c.Config.NetworkDisabled {
@@ -247,18 +264,40 @@ func populateCommand(c *Container, env []string) error {
 		rlimits = append(rlimits, rl)
 	}
 
+	readBpsDevice, err := getBlkioThrottleDevices(c.hostConfig.BlkioDeviceReadBps)
+	if err != nil {
+		return err
+	}
+	writeBpsDevice, err := getBlkioThrottleDevices(c.hostConfig.BlkioDeviceWriteBps)
+	if err != nil {
+		return err
+	}
+	readIOpsDevice, err := getBlkioThrottleDevices(c.hostConfig.BlkioDeviceReadIOps)
+	if err != nil {
+		return err
+	}
+	writeIOpsDevice, err := getBlkioThrottleDevices(c.hostConfig.BlkioDeviceWriteIOps)
+	if err != nil {
+		return err
+	}
+
 	resources := &execdriver.Resources{
-		Memory:           c.hostConfig.Memory,
-		MemorySwap:       c.hostConfig.MemorySwap,
-		CPUShares:        c.hostConfig.CPUShares,
-		CpusetCpus:       c.hostConfig.CpusetCpus,
-		CpusetMems:       c.hostConfig.CpusetMems,
-		CPUPeriod:        c.hostConfig.CPUPeriod,
-		CPUQuota:         c.hostConfig.CPUQuota,
-		BlkioWeight:      c.hostConfig.BlkioWeight,
-		Rlimits:          rlimits,
-		OomKillDisable:   c.hostConfig.OomKillDisable,
-		MemorySwappiness: -1,
+		Memory:                       c.hostConfig.Memory,
+		MemorySwap:                   c.hostConfig.MemorySwap,
+		CPUShares:                    c.hostConfig.CPUShares,
+		CpusetCpus:                   c.hostConfig.CpusetCpus,
+		CpusetMems:                   c.hostConfig.CpusetMems,
+		CPUPeriod:                    c.hostConfig.CPUPeriod,
+		CPUQuota:                     c.hostConfig.CPUQuota,
+		BlkioWeight:                  c.hostConfig.BlkioWeight,
+		BlkioThrottleReadBpsDevice:   readBpsDevice,
+		BlkioThrottleWriteBpsDevice:  writeBpsDevice,
+		BlkioThrottleReadIOpsDevice:  readIOpsDevice,
+		BlkioThrottleWriteIOpsDevice: writeIOpsDevice,
+		Rlimits:                      rlimits,
+		OomKillDisable:               c.hostConfig.OomKillDisable,
+		MemorySwappiness:             -1,
+		PidsLimit:                    c.hostConfig.PidsLimit,
 	}
 
 	if c.hostConfig.MemorySwappiness != nil {
@@ -297,9 +336,15 @@ func populateCommand(c *Container, env []string) error {
 		MountLabel:         c.GetMountLabel(),
 		LxcConfig:          lxcConfig,
 		AppArmorProfile:    c.AppArmorProfile,
+		SeccompProfile:     c.SeccompProfile,
 		CgroupParent:       c.hostConfig.CgroupParent,
+		LiveRestore:        c.daemon.config.LiveRestore,
 	}
 
+	uidMap, gidMap := c.daemon.GetUIDGIDMaps()
+	c.command.UIDMapping = uidMap
+	c.command.GIDMapping = gidMap
+
 	return nil
 }
 
@@ -871,6 +916,7 @@ func (container *Container) configureNetwork(networkName, service, networkDriver
 		if n, err = createNetwork(controller, networkName, networkDriver); err != nil {
 			return err
 		}
+		container.daemon.LogNetworkEvent(n, "create")
 	}
 
 	ep, err := n.EndpointByName(service)
@@ -902,6 +948,7 @@ func (container *Container) configureNetwork(networkName, service, networkDriver
 	if err := ep.Join(container.ID, joinOptions...); err != nil {
 		return err
 	}
+	container.daemon.LogNetworkEventWithAttributes(n, "connect", map[string]string{"container": container.ID})
 
 	if err := container.updateJoinInfo(ep); err != nil {
 		return fmt.Errorf("Updating join info failed: %v", err)
@@ -995,7 +1042,8 @@ func (container *Container) setupWorkingDirectory() error {
 				return err
 			}
 
-			if err := system.MkdirAll(pth, 0755); err != nil {
+			rootUID, rootGID := container.daemon.GetRemappedUIDGID()
+			if err := idtools.MkdirAllAs(pth, 0755, rootUID, rootGID); err != nil {
 				return err
 			}
 		}
@@ -1067,6 +1115,7 @@ func (container *Container) ReleaseNetwork() {
 			return
 		}
 	}
+	container.daemon.LogNetworkEventWithAttributes(n, "disconnect", map[string]string{"container": container.ID})
 
 	// In addition to leaving all endpoints, delete implicitly created endpoint
 	if container.Config.PublishService == "" {
@@ -1191,7 +1240,7 @@ func (container *Container) isDestinationMounted(destination string) bool {
 func (container *Container) prepareMountPoints() error {
 	for _, config := range container.MountPoints {
 		if len(config.Driver) > 0 {
-			v, err := createVolume(config.Name, config.Driver)
+			v, err := container.daemon.createVolume(config.Name, config.Driver)
 			if err != nil {
 				return err
 			}
@@ -1201,13 +1250,27 @@ func (container *Container) prepareMountPoints() error {
 	return nil
 }
 
-func (container *Container) removeMountPoints() error {
+func (container *Container) removeMountPoints(rm bool) error {
+	var rmErrors []string
 	for _, m := range container.MountPoints {
-		if m.Volume != nil {
-			if err := removeVolume(m.Volume); err != nil {
-				return err
+		if m.Volume == nil {
+			continue
+		}
+		container.daemon.volumes.Decrement(m.Volume)
+		if rm {
+			// ErrVolumeInUse is not an error here: the volume is
+			// still referenced by another container and will be
+			// kept around for it.
+			err := container.daemon.volumes.Remove(m.Volume)
+			if err == nil {
+				container.daemon.LogVolumeEvent(m.Volume.Name(), "destroy", map[string]string{"driver": m.Volume.DriverName()})
+			} else if err != store.ErrVolumeInUse {
+				rmErrors = append(rmErrors, err.Error())
 			}
 		}
 	}
+	if len(rmErrors) > 0 {
+		return fmt.Errorf("Error removing volumes:\n%v", strings.Join(rmErrors, "\n"))
+	}
 	return nil
 }
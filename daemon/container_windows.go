@@ -206,6 +206,6 @@ func (container *Container) prepareMountPoints() error {
 }
 
 // removeMountPoints is a no-op on Windows.
-func (container *Container) removeMountPoints() error {
+func (container *Container) removeMountPoints(rm bool) error {
 	return nil
 }
@@ -42,7 +42,7 @@ func createContainerPlatformSpecificSettings(container *Container, config *runco
 			return fmt.Errorf("cannot mount volume over existing file, file exists %s", path)
 		}
 
-		v, err := createVolume(name, config.VolumeDriver)
+		v, err := container.daemon.createVolume(name, config.VolumeDriver)
 		if err != nil {
 			return err
 		}
@@ -27,9 +27,11 @@ import (
 	"github.com/docker/docker/pkg/broadcastwriter"
 	"github.com/docker/docker/pkg/fileutils"
 	"github.com/docker/docker/pkg/graphdb"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/namesgenerator"
 	"github.com/docker/docker/pkg/nat"
+	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/pkg/sysinfo"
 	"github.com/docker/docker/pkg/system"
@@ -37,7 +39,9 @@ import (
 	"github.com/docker/docker/registry"
 	"github.com/docker/docker/runconfig"
 	"github.com/docker/docker/trust"
+	"github.com/docker/docker/volume/store"
 	"github.com/docker/libnetwork"
+	"github.com/opencontainers/runc/libcontainer"
 	"github.com/opencontainers/runc/libcontainer/netlink"
 )
 
@@ -94,6 +98,7 @@ type Daemon struct {
 	idIndex          *truncindex.TruncIndex
 	sysInfo          *sysinfo.SysInfo
 	config           *Config
+	configLock       sync.RWMutex // protects the options changed by Reload
 	containerGraph   *graphdb.Database
 	driver           graphdriver.Driver
 	execDriver       execdriver.Driver
@@ -102,7 +107,10 @@ type Daemon struct {
 	RegistryService  *registry.Service
 	EventsService    *events.Events
 	netController    libnetwork.NetworkController
+	volumes          *store.VolumeStore
 	root             string
+	uidMaps          []idtools.IDMap
+	gidMaps          []idtools.IDMap
 }
 
 // Get looks for a container using the provided information, which could be
@@ -202,26 +210,33 @@ func (daemon *Daemon) register(container *Container, updateSuffixarray bool) err
 		return err
 	}
 
-	if container.IsRunning() {
-		logrus.Debugf("killing old running container %s", container.ID)
-		// Set exit code to 128 + SIGKILL (9) to properly represent unsuccessful exit
-		container.SetStopped(&execdriver.ExitStatus{ExitCode: 137})
+	// with live restore, the running containers are reattached once they
+	// are all registered
+	if container.IsRunning() && !daemon.config.LiveRestore {
+		daemon.killOldContainer(container)
+	}
 
-		// use the current driver and ensure that the container is dead x.x
-		cmd := &execdriver.Command{
-			ID: container.ID,
-		}
-		daemon.execDriver.Terminate(cmd)
+	return nil
+}
 
-		if err := container.Unmount(); err != nil {
-			logrus.Debugf("unmount error %s", err)
-		}
-		if err := container.ToDisk(); err != nil {
-			logrus.Errorf("Error saving stopped state to disk: %v", err)
-		}
+// killOldContainer kills a container left running by a previous daemon.
+func (daemon *Daemon) killOldContainer(container *Container) {
+	logrus.Debugf("killing old running container %s", container.ID)
+	// Set exit code to 128 + SIGKILL (9) to properly represent unsuccessful exit
+	container.SetStopped(&execdriver.ExitStatus{ExitCode: 137})
+
+	// use the current driver and ensure that the container is dead x.x
+	cmd := &execdriver.Command{
+		ID: container.ID,
 	}
+	daemon.execDriver.Terminate(cmd)
 
-	return nil
+	if err := container.Unmount(); err != nil {
+		logrus.Debugf("unmount error %s", err)
+	}
+	if err := container.ToDisk(); err != nil {
+		logrus.Errorf("Error saving stopped state to disk: %v", err)
+	}
 }
 
 func (daemon *Daemon) ensureName(container *Container) error {
@@ -274,6 +289,15 @@ func (daemon *Daemon) restore() error {
 		if (container.Driver == "" && currentDriver == "aufs") || container.Driver == currentDriver {
 			logrus.Debugf("Loaded container %v", container.ID)
 
+			// The image of a container created before the images were
+			// content-addressable is named by its v1 ID.
+			if img, err := daemon.graph.Get(container.ImageID); err == nil && img.ID != container.ImageID {
+				container.ImageID = img.ID
+				if err := container.toDisk(); err != nil {
+					logrus.Errorf("Failed to save container %s: %v", container.ID, err)
+				}
+			}
+
 			containers[container.ID] = &cr{container: container}
 		} else {
 			logrus.Debugf("Cannot load container %s because it was created with another graph driver.", container.ID)
@@ -313,8 +337,28 @@ func (daemon *Daemon) restore() error {
 				logrus.Debugf("Failed to register container %s: %s", container.ID, err)
 			}
 
+			// containers kept running by live restore are reattached,
+			// or killed when their process cannot be reattached
+			if daemon.config.LiveRestore && container.IsRunning() {
+				logrus.Debugf("Reattaching container %s", container.ID)
+				if err := container.reattach(); err != nil {
+					logrus.Errorf("Failed to reattach container %s: %s", container.ID, err)
+					daemon.killOldContainer(container)
+				} else {
+					return
+				}
+			}
+
+			// containers created with --rm that were left behind, for
+			// example by a crash of the daemon, are removed
+			if container.shouldAutoRemove() {
+				daemon.autoRemove(container)
+				return
+			}
+
 			// check the restart policy on the containers and restart any container with
-			// the restart policy of "always"
+			// the restart policy of "always", or "unless-stopped" when they were not
+			// stopped by the user
 			if daemon.config.AutoRestart && container.shouldRestart() {
 				logrus.Debugf("Starting container %s", container.ID)
 
@@ -571,6 +615,15 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	// on Windows to dump Go routine stacks
 	setupDumpStackTrap()
 
+	uidMaps, gidMaps, err := setupRemappedRoot(config)
+	if err != nil {
+		return nil, err
+	}
+	rootUID, rootGID, err := idtools.GetRootUIDGID(uidMaps, gidMaps)
+	if err != nil {
+		return nil, err
+	}
+
 	// get the canonical path to the Docker root directory
 	var realRoot string
 	if _, err := os.Stat(config.Root); err != nil && os.IsNotExist(err) {
@@ -581,9 +634,8 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 			return nil, fmt.Errorf("Unable to get the full path to root (%s): %s", config.Root, err)
 		}
 	}
-	config.Root = realRoot
-	// Create the root directory if it doesn't exists
-	if err := system.MkdirAll(config.Root, 0700); err != nil {
+
+	if err = setupDaemonRoot(config, realRoot, rootUID, rootGID); err != nil {
 		return nil, err
 	}
 
@@ -602,7 +654,7 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	graphdriver.DefaultDriver = config.GraphDriver
 
 	// Load storage driver
-	driver, err := graphdriver.New(config.Root, config.GraphOptions)
+	driver, err := graphdriver.New(config.Root, config.GraphOptions, uidMaps, gidMaps)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing graphdriver: %v", err)
 	}
@@ -610,6 +662,8 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 
 	d := &Daemon{}
 	d.driver = driver
+	d.uidMaps = uidMaps
+	d.gidMaps = gidMaps
 
 	// Ensure the graph driver is shutdown at a later point
 	defer func() {
@@ -635,7 +689,8 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 
 	daemonRepo := filepath.Join(config.Root, "containers")
 
-	if err := system.MkdirAll(daemonRepo, 0700); err != nil {
+	// Create the container repository owned by the remapped root
+	if err := idtools.MkdirAllAs(daemonRepo, 0700, rootUID, rootGID); err != nil {
 		return nil, err
 	}
 
@@ -645,13 +700,17 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	}
 
 	logrus.Debug("Creating images graph")
-	g, err := graph.NewGraph(filepath.Join(config.Root, "graph"), d.driver)
+	g, err := graph.NewGraph(filepath.Join(config.Root, "image", d.driver.String()), d.driver)
 	if err != nil {
 		return nil, err
 	}
+	if err := g.Migrate(filepath.Join(config.Root, "graph")); err != nil {
+		return nil, fmt.Errorf("Couldn't migrate the images graph: %v", err)
+	}
 
 	// Configure the volumes driver
-	if err := configureVolumes(config); err != nil {
+	volStore, err := configureVolumes(config, rootUID, rootGID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -670,7 +729,11 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 		return nil, fmt.Errorf("could not create trust store: %s", err)
 	}
 
-	eventsService := events.New()
+	journal, err := events.NewJournal(filepath.Join(config.Root, "events"), events.DefaultJournalMaxSize, events.DefaultJournalMaxFiles)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't open the events journal: %v", err)
+	}
+	eventsService := events.NewWithJournal(journal)
 	logrus.Debug("Creating repository list")
 	tagCfg := &graph.TagStoreConfig{
 		Graph:    g,
@@ -733,6 +796,7 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	d.defaultLogConfig = config.LogConfig
 	d.RegistryService = registryService
 	d.EventsService = eventsService
+	d.volumes = volStore
 	d.root = config.Root
 	go d.execCommandGC()
 
@@ -749,6 +813,12 @@ func (daemon *Daemon) Shutdown() error {
 		logrus.Debug("starting clean shutdown of all containers...")
 		for _, container := range daemon.List() {
 			c := container
+			// containers started with live restore keep running, their
+			// process is reattached by the next daemon
+			if c.IsRunning() && c.command != nil && c.command.LiveRestore {
+				logrus.Debugf("leaving %s running", c.ID)
+				continue
+			}
 			if c.IsRunning() {
 				logrus.Debugf("stopping %s", c.ID)
 				group.Add(1)
@@ -784,6 +854,12 @@ func (daemon *Daemon) Shutdown() error {
 		}
 	}
 
+	if daemon.EventsService != nil {
+		if err := daemon.EventsService.Close(); err != nil {
+			logrus.Errorf("Error during events journal Close(): %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -816,6 +892,23 @@ func (daemon *Daemon) Run(c *Container, pipes *execdriver.Pipes, startCallback e
 	return daemon.execDriver.Run(c.command, pipes, startCallback)
 }
 
+// Checkpoint saves the state of the processes of a container to disk.
+func (daemon *Daemon) Checkpoint(c *Container, opts *libcontainer.CriuOpts) error {
+	return daemon.execDriver.Checkpoint(c.command, opts)
+}
+
+// Restore restores the processes of a container from a checkpoint and waits
+// for the restored process to exit.
+func (daemon *Daemon) Restore(c *Container, pipes *execdriver.Pipes, restoreCallback execdriver.StartCallback, opts *libcontainer.CriuOpts) (execdriver.ExitStatus, error) {
+	return daemon.execDriver.Restore(c.command, pipes, restoreCallback, opts)
+}
+
+// Reattach attaches to the process of a container kept running by live
+// restore and waits for it to exit.
+func (daemon *Daemon) Reattach(c *Container, pipes *execdriver.Pipes, reattachCallback execdriver.StartCallback) (execdriver.ExitStatus, error) {
+	return daemon.execDriver.Reattach(c.command, pipes, reattachCallback)
+}
+
 func (daemon *Daemon) Kill(c *Container, sig int) error {
 	return daemon.execDriver.Kill(c.command, sig)
 }
@@ -870,6 +963,19 @@ func (daemon *Daemon) GraphDriver() graphdriver.Driver {
 	return daemon.driver
 }
 
+// GetUIDGIDMaps returns the user and group ID mappings of the remapped root
+// of the daemon, which are nil when it is not remapped.
+func (daemon *Daemon) GetUIDGIDMaps() ([]idtools.IDMap, []idtools.IDMap) {
+	return daemon.uidMaps, daemon.gidMaps
+}
+
+// GetRemappedUIDGID returns the host user and group IDs the root user of the
+// containers maps to.
+func (daemon *Daemon) GetRemappedUIDGID() (int, int) {
+	uid, gid, _ := idtools.GetRootUIDGID(daemon.uidMaps, daemon.gidMaps)
+	return uid, gid
+}
+
 func (daemon *Daemon) ExecutionDriver() execdriver.Driver {
 	return daemon.execDriver
 }
@@ -978,6 +1084,11 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *runconfig.HostConfig,
 		if config.WorkingDir != "" && !filepath.IsAbs(config.WorkingDir) {
 			return nil, fmt.Errorf("The working directory '%s' is invalid. It needs to be an absolute path.", config.WorkingDir)
 		}
+		if config.StopSignal != "" {
+			if _, err := signal.ParseSignal(config.StopSignal); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if hostConfig == nil {
@@ -997,6 +1108,10 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *runconfig.HostConfig,
 		}
 	}
 
+	if hostConfig.AutoRemove && hostConfig.RestartPolicy.Name != "" && !hostConfig.RestartPolicy.IsNone() {
+		return nil, fmt.Errorf("Conflicting options: a container with a restart policy cannot be removed automatically")
+	}
+
 	// Now do platform-specific verification
 	return verifyPlatformContainerSettings(daemon, hostConfig, config)
 }
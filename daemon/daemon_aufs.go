@@ -13,7 +13,12 @@ import (
 func migrateIfAufs(driver graphdriver.Driver, root string) error {
 	if ad, ok := driver.(*aufs.Driver); ok {
 		logrus.Debugf("Migrating existing containers")
-		if err := ad.Migrate(root, setupInitLayer); err != nil {
+		// the layouts to migrate predate the remapped daemon roots, so
+		// the init layers stay owned by the real root
+		setupInit := func(initLayer string) error {
+			return setupInitLayer(initLayer, 0, 0)
+		}
+		if err := ad.Migrate(root, setupInit); err != nil {
 			return err
 		}
 	}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/docker/docker/daemon/graphdriver"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/fileutils"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/parsers"
 	"github.com/docker/docker/pkg/parsers/kernel"
 	"github.com/docker/docker/pkg/system"
@@ -23,12 +25,14 @@ import (
 	"github.com/docker/docker/utils"
 	volumedrivers "github.com/docker/docker/volume/drivers"
 	"github.com/docker/docker/volume/local"
+	"github.com/docker/docker/volume/store"
 	"github.com/docker/libnetwork"
 	nwapi "github.com/docker/libnetwork/api"
 	nwconfig "github.com/docker/libnetwork/config"
 	"github.com/docker/libnetwork/netlabel"
 	"github.com/docker/libnetwork/options"
 	"github.com/opencontainers/runc/libcontainer/label"
+	"github.com/opencontainers/runc/libcontainer/user"
 )
 
 func (daemon *Daemon) Changes(container *Container) ([]archive.Change, error) {
@@ -48,15 +52,19 @@ func parseSecurityOpt(container *Container, config *runconfig.HostConfig) error
 	)
 
 	for _, opt := range config.SecurityOpt {
-		con := strings.SplitN(opt, ":", 2)
-		if len(con) == 1 {
+		// options are given as key:value or key=value
+		i := strings.IndexAny(opt, ":=")
+		if i == -1 {
 			return fmt.Errorf("Invalid --security-opt: %q", opt)
 		}
-		switch con[0] {
+		key, value := opt[:i], opt[i+1:]
+		switch key {
 		case "label":
-			labelOpts = append(labelOpts, con[1])
+			labelOpts = append(labelOpts, value)
 		case "apparmor":
-			container.AppArmorProfile = con[1]
+			container.AppArmorProfile = value
+		case "seccomp":
+			container.SeccompProfile = value
 		default:
 			return fmt.Errorf("Invalid --security-opt: %q", opt)
 		}
@@ -69,11 +77,20 @@ func parseSecurityOpt(container *Container, config *runconfig.HostConfig) error
 func (daemon *Daemon) createRootfs(container *Container) error {
 	// Step 1: create the container directory.
 	// This doubles as a barrier to avoid race conditions.
-	if err := os.Mkdir(container.root, 0700); err != nil {
+	rootUID, rootGID := daemon.GetRemappedUIDGID()
+	if err := idtools.MkdirAs(container.root, 0700, rootUID, rootGID); err != nil {
 		return err
 	}
+	var parent string
+	if container.ImageID != "" {
+		cacheID, err := daemon.graph.CacheID(container.ImageID)
+		if err != nil {
+			return err
+		}
+		parent = cacheID
+	}
 	initID := fmt.Sprintf("%s-init", container.ID)
-	if err := daemon.driver.Create(initID, container.ImageID); err != nil {
+	if err := daemon.driver.Create(initID, parent); err != nil {
 		return err
 	}
 	initPath, err := daemon.driver.Get(initID, "")
@@ -81,7 +98,7 @@ func (daemon *Daemon) createRootfs(container *Container) error {
 		return err
 	}
 
-	if err := setupInitLayer(initPath); err != nil {
+	if err := setupInitLayer(initPath, rootUID, rootGID); err != nil {
 		daemon.driver.Put(initID)
 		return err
 	}
@@ -176,9 +193,26 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *runconfig.HostC
 		logrus.Warnf("Your kernel does not support CPU cfs quota. Quota discarded.")
 		hostConfig.CPUQuota = 0
 	}
+	if hostConfig.PidsLimit != 0 && !daemon.SystemConfig().PidsLimit {
+		warnings = append(warnings, "Your kernel does not support pids limit capabilities. Pids limit discarded.")
+		logrus.Warnf("Your kernel does not support pids limit capabilities. Pids limit discarded.")
+		hostConfig.PidsLimit = 0
+	}
 	if hostConfig.BlkioWeight > 0 && (hostConfig.BlkioWeight < 10 || hostConfig.BlkioWeight > 1000) {
 		return warnings, fmt.Errorf("Range of blkio weight is from 10 to 1000.")
 	}
+	if len(hostConfig.BlkioDeviceReadBps) > 0 && !daemon.SystemConfig().BlkioReadBpsDevice {
+		return warnings, fmt.Errorf("Your kernel does not support Block read limit in bytes per second.")
+	}
+	if len(hostConfig.BlkioDeviceWriteBps) > 0 && !daemon.SystemConfig().BlkioWriteBpsDevice {
+		return warnings, fmt.Errorf("Your kernel does not support Block write limit in bytes per second.")
+	}
+	if len(hostConfig.BlkioDeviceReadIOps) > 0 && !daemon.SystemConfig().BlkioReadIOpsDevice {
+		return warnings, fmt.Errorf("Your kernel does not support Block read limit in IO per second.")
+	}
+	if len(hostConfig.BlkioDeviceWriteIOps) > 0 && !daemon.SystemConfig().BlkioWriteIOpsDevice {
+		return warnings, fmt.Errorf("Your kernel does not support Block write limit in IO per second.")
+	}
 	if hostConfig.OomKillDisable && !daemon.SystemConfig().OomKillDisable {
 		hostConfig.OomKillDisable = false
 		return warnings, fmt.Errorf("Your kernel does not support oom kill disable.")
@@ -202,6 +236,9 @@ func checkConfigOptions(config *Config) error {
 	if !config.Bridge.EnableIPTables && config.Bridge.EnableIPMasq {
 		config.Bridge.EnableIPMasq = false
 	}
+	if config.LiveRestore && config.ExecDriver == "lxc" {
+		return fmt.Errorf("You specified --live-restore with the lxc exec driver. Live restore is only supported by the native exec driver.")
+	}
 	return nil
 }
 
@@ -234,18 +271,115 @@ func configureKernelSecuritySupport(config *Config, driverName string) error {
 	return nil
 }
 
+// setupRemappedRoot parses the --userns-remap setting, a user and an optional
+// group given by name or ID, and returns the ID mappings of their subordinate
+// ranges. The mappings are nil when no remapping is configured.
+func setupRemappedRoot(config *Config) ([]idtools.IDMap, []idtools.IDMap, error) {
+	if config.RemappedRoot == "" {
+		return nil, nil, nil
+	}
+	if config.ExecDriver != "native" {
+		return nil, nil, fmt.Errorf("User namespaces are only supported with the native execdriver")
+	}
+
+	parts := strings.SplitN(config.RemappedRoot, ":", 2)
+	if parts[0] == "" {
+		return nil, nil, fmt.Errorf("Invalid --userns-remap %q: the user can not be empty", config.RemappedRoot)
+	}
+	username, err := lookupRemappedUser(parts[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if username == "root" {
+		return nil, nil, fmt.Errorf("Can not remap the root of the containers to the root user")
+	}
+	groupname := username
+	if len(parts) == 2 && parts[1] != "" {
+		if groupname, err = lookupRemappedGroup(parts[1]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	uidMaps, gidMaps, err := idtools.CreateIDMappings(username, groupname)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Can't create ID mappings for %s:%s: %v", username, groupname, err)
+	}
+	logrus.Infof("User namespaces: the root of the containers is remapped to %s:%s", username, groupname)
+	return uidMaps, gidMaps, nil
+}
+
+// lookupRemappedUser returns the name of the user given by name or ID, as the
+// subordinate ID files are keyed by name.
+func lookupRemappedUser(name string) (string, error) {
+	if uid, err := strconv.Atoi(name); err == nil {
+		u, err := user.LookupUid(uid)
+		if err != nil {
+			return "", fmt.Errorf("Can't find the user with ID %d: %v", uid, err)
+		}
+		return u.Name, nil
+	}
+	if _, err := user.LookupUser(name); err != nil {
+		return "", fmt.Errorf("Can't find the user %s: %v", name, err)
+	}
+	return name, nil
+}
+
+// lookupRemappedGroup returns the name of the group given by name or ID.
+func lookupRemappedGroup(name string) (string, error) {
+	if gid, err := strconv.Atoi(name); err == nil {
+		g, err := user.LookupGid(gid)
+		if err != nil {
+			return "", fmt.Errorf("Can't find the group with ID %d: %v", gid, err)
+		}
+		return g.Name, nil
+	}
+	if _, err := user.LookupGroup(name); err != nil {
+		return "", fmt.Errorf("Can't find the group %s: %v", name, err)
+	}
+	return name, nil
+}
+
+// setupDaemonRoot creates the root directory of the daemon. When the root of
+// the containers is remapped, the state of the daemon is kept in a
+// subdirectory per mapping, owned by the remapped root, so that the layers
+// and volumes of differently remapped daemons are never shared.
+func setupDaemonRoot(config *Config, rootDir string, rootUID, rootGID int) error {
+	config.Root = rootDir
+	// Create the root directory if it doesn't exists
+	if err := system.MkdirAll(rootDir, 0700); err != nil {
+		return err
+	}
+	if config.RemappedRoot == "" {
+		return nil
+	}
+
+	// the top-level root stays private, except for the traversal needed
+	// by the remapped root to reach its own subdirectory
+	if err := os.Chmod(rootDir, 0701); err != nil {
+		return err
+	}
+	config.Root = filepath.Join(rootDir, fmt.Sprintf("%d.%d", rootUID, rootGID))
+	logrus.Debugf("Creating user namespaced daemon root: %s", config.Root)
+	if err := idtools.MkdirAllAs(config.Root, 0700, rootUID, rootGID); err != nil {
+		return fmt.Errorf("Cannot create daemon root: %s: %v", config.Root, err)
+	}
+	return nil
+}
+
 // MigrateIfDownlevel is a wrapper for AUFS migration for downlevel
 func migrateIfDownlevel(driver graphdriver.Driver, root string) error {
 	return migrateIfAufs(driver, root)
 }
 
-func configureVolumes(config *Config) error {
-	volumesDriver, err := local.New(config.Root)
+func configureVolumes(config *Config, rootUID, rootGID int) (*store.VolumeStore, error) {
+	volumesDriver, err := local.New(config.Root, rootUID, rootGID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	volumedrivers.Register(volumesDriver, volumesDriver.Name())
-	return nil
+	s := store.New()
+	s.AddAll(volumesDriver.List())
+	return s, nil
 }
 
 func configureSysInit(config *Config) (string, error) {
@@ -425,7 +559,7 @@ func initBridgeDriver(controller libnetwork.NetworkController, config *Config) e
 //
 // This extra layer is used by all containers as the top-most ro layer. It protects
 // the container from unwanted side-effects on the rw layer.
-func setupInitLayer(initLayer string) error {
+func setupInitLayer(initLayer string, rootUID, rootGID int) error {
 	for pth, typ := range map[string]string{
 		"/dev/pts":         "dir",
 		"/dev/shm":         "dir",
@@ -448,12 +582,12 @@ func setupInitLayer(initLayer string) error {
 
 		if _, err := os.Stat(filepath.Join(initLayer, pth)); err != nil {
 			if os.IsNotExist(err) {
-				if err := system.MkdirAll(filepath.Join(initLayer, filepath.Dir(pth)), 0755); err != nil {
+				if err := idtools.MkdirAllAs(filepath.Join(initLayer, filepath.Dir(pth)), 0755, rootUID, rootGID); err != nil {
 					return err
 				}
 				switch typ {
 				case "dir":
-					if err := system.MkdirAll(filepath.Join(initLayer, pth), 0755); err != nil {
+					if err := idtools.MkdirAllAs(filepath.Join(initLayer, pth), 0755, rootUID, rootGID); err != nil {
 						return err
 					}
 				case "file":
@@ -461,11 +595,15 @@ func setupInitLayer(initLayer string) error {
 					if err != nil {
 						return err
 					}
+					f.Chown(rootUID, rootGID)
 					f.Close()
 				default:
 					if err := os.Symlink(typ, filepath.Join(initLayer, pth)); err != nil {
 						return err
 					}
+					if err := os.Lchown(filepath.Join(initLayer, pth), rootUID, rootGID); err != nil {
+						return err
+					}
 				}
 			} else {
 				return err
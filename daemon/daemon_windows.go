@@ -9,8 +9,11 @@ import (
 	"github.com/docker/docker/daemon/graphdriver"
 	"github.com/docker/docker/daemon/graphdriver/windows"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/parsers"
+	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/runconfig"
+	"github.com/docker/docker/volume/store"
 	"github.com/docker/libnetwork"
 	"github.com/microsoft/hcsshim"
 )
@@ -18,11 +21,28 @@ import (
 const DefaultVirtualSwitch = "Virtual Switch"
 
 func (daemon *Daemon) Changes(container *Container) ([]archive.Change, error) {
-	return daemon.driver.Changes(container.ID, container.ImageID)
+	parent, err := daemon.imageCacheID(container)
+	if err != nil {
+		return nil, err
+	}
+	return daemon.driver.Changes(container.ID, parent)
 }
 
 func (daemon *Daemon) Diff(container *Container) (archive.Archive, error) {
-	return daemon.driver.Diff(container.ID, container.ImageID)
+	parent, err := daemon.imageCacheID(container)
+	if err != nil {
+		return nil, err
+	}
+	return daemon.driver.Diff(container.ID, parent)
+}
+
+// imageCacheID returns the storage driver ID of the layer of the image of
+// the container, or "" when it has no image.
+func (daemon *Daemon) imageCacheID(container *Container) (string, error) {
+	if container.ImageID == "" {
+		return "", nil
+	}
+	return daemon.graph.CacheID(container.ImageID)
 }
 
 func parseSecurityOpt(container *Container, config *runconfig.HostConfig) error {
@@ -36,6 +56,11 @@ func (daemon *Daemon) createRootfs(container *Container) error {
 		return err
 	}
 
+	parent, err := daemon.imageCacheID(container)
+	if err != nil {
+		return err
+	}
+
 	if wd, ok := daemon.driver.(*windows.WindowsGraphDriver); ok {
 		if container.ImageID != "" {
 			// Get list of paths to parent layers.
@@ -51,17 +76,17 @@ func (daemon *Daemon) createRootfs(container *Container) error {
 			}
 			logrus.Debugf("Got image ids: %d", len(ids))
 
-			if err := hcsshim.CreateSandboxLayer(wd.Info(), container.ID, container.ImageID, wd.LayerIdsToPaths(ids)); err != nil {
+			if err := hcsshim.CreateSandboxLayer(wd.Info(), container.ID, parent, wd.LayerIdsToPaths(ids)); err != nil {
 				return err
 			}
 		} else {
-			if err := daemon.driver.Create(container.ID, container.ImageID); err != nil {
+			if err := daemon.driver.Create(container.ID, parent); err != nil {
 				return err
 			}
 		}
 	} else {
 		// Fall-back code path to allow the use of the VFS driver for development
-		if err := daemon.driver.Create(container.ID, container.ImageID); err != nil {
+		if err := daemon.driver.Create(container.ID, parent); err != nil {
 			return err
 		}
 
@@ -118,11 +143,27 @@ func migrateIfDownlevel(driver graphdriver.Driver, root string) error {
 	return nil
 }
 
-func configureVolumes(config *Config) error {
-	// Windows does not support volumes at this time
+// setupRemappedRoot is a no-op on Windows, which does not support user
+// namespaces.
+func setupRemappedRoot(config *Config) ([]idtools.IDMap, []idtools.IDMap, error) {
+	return nil, nil, nil
+}
+
+// setupDaemonRoot creates the root directory of the daemon.
+func setupDaemonRoot(config *Config, rootDir string, rootUID, rootGID int) error {
+	config.Root = rootDir
+	// Create the root directory if it doesn't exists
+	if err := system.MkdirAll(config.Root, 0700); err != nil {
+		return err
+	}
 	return nil
 }
 
+func configureVolumes(config *Config, rootUID, rootGID int) (*store.VolumeStore, error) {
+	// Windows does not support volumes at this time
+	return store.New(), nil
+}
+
 func configureSysInit(config *Config) (string, error) {
 	// TODO Windows.
 	return os.Getenv("TEMP"), nil
@@ -51,12 +51,19 @@ func (daemon *Daemon) ContainerRm(name string, config *ContainerRmConfig) error
 		return fmt.Errorf("Cannot destroy container %s: %v", name, err)
 	}
 
-	if config.RemoveVolume {
-		container.removeMountPoints()
+	if err := container.removeMountPoints(config.RemoveVolume); err != nil {
+		logrus.Error(err)
 	}
 	return nil
 }
 
+// autoRemove removes a container created with --rm along with its volumes.
+func (daemon *Daemon) autoRemove(container *Container) {
+	if err := daemon.ContainerRm(container.ID, &ContainerRmConfig{ForceRemove: true, RemoveVolume: true}); err != nil {
+		logrus.Errorf("Error removing container %s: %v", container.ID, err)
+	}
+}
+
 // Destroy unregisters a container from the daemon and cleanly removes its contents from the filesystem.
 func (daemon *Daemon) rm(container *Container, forceRemove bool) (err error) {
 	if container.IsRunning() {
@@ -140,7 +147,3 @@ func (daemon *Daemon) rm(container *Container, forceRemove bool) (err error) {
 	container.LogEvent("destroy")
 	return nil
 }
-
-func (daemon *Daemon) DeleteVolumes(c *Container) error {
-	return c.removeMountPoints()
-}
@@ -4,39 +4,75 @@ import (
 	"sync"
 	"time"
 
-	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/Sirupsen/logrus"
+	eventtypes "github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/pkg/pubsub"
 )
 
 const eventsLimit = 64
 
-// Events is pubsub channel for *jsonmessage.JSONMessage
+// Events is pubsub channel for events generated by the engine.
 type Events struct {
-	mu     sync.Mutex
-	events []*jsonmessage.JSONMessage
-	pub    *pubsub.Publisher
+	mu      sync.RWMutex
+	events  []eventtypes.Message
+	pub     *pubsub.Publisher
+	journal *Journal
 }
 
 // New returns new *Events instance
 func New() *Events {
+	return NewWithJournal(nil)
+}
+
+// NewWithJournal returns new *Events instance which also records every
+// event in journal, so that past events can be replayed after they have
+// been dropped from memory or after a restart.
+func NewWithJournal(journal *Journal) *Events {
 	return &Events{
-		events: make([]*jsonmessage.JSONMessage, 0, eventsLimit),
-		pub:    pubsub.NewPublisher(100*time.Millisecond, 1024),
+		events:  make([]eventtypes.Message, 0, eventsLimit),
+		pub:     pubsub.NewPublisher(100*time.Millisecond, 1024),
+		journal: journal,
 	}
 }
 
 // Subscribe adds new listener to events, returns slice of 64 stored last events
 // channel in which you can expect new events in form of interface{}, so you
 // need type assertion.
-func (e *Events) Subscribe() ([]*jsonmessage.JSONMessage, chan interface{}) {
-	e.mu.Lock()
-	current := make([]*jsonmessage.JSONMessage, len(e.events))
+func (e *Events) Subscribe() ([]eventtypes.Message, chan interface{}) {
+	e.mu.RLock()
+	current := make([]eventtypes.Message, len(e.events))
 	copy(current, e.events)
 	l := e.pub.Subscribe()
-	e.mu.Unlock()
+	e.mu.RUnlock()
 	return current, l
 }
 
+// SubscribeRange adds new listener to events and returns the events logged
+// between since and until (Unix timestamps, an until of zero or less means
+// no upper bound). Past events are replayed from the journal if there is
+// one, otherwise only the events still kept in memory are returned.
+// No event is both returned and sent to the listener.
+func (e *Events) SubscribeRange(since, until int64) ([]eventtypes.Message, chan interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var current []eventtypes.Message
+	if e.journal != nil {
+		var err error
+		current, err = e.journal.Read(since, until)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		for _, ev := range e.events {
+			if inRange(ev, since, until) {
+				current = append(current, ev)
+			}
+		}
+	}
+	return current, e.pub.Subscribe(), nil
+}
+
 // Evict evicts listener from pubsub
 func (e *Events) Evict(l chan interface{}) {
 	e.pub.Evict(l)
@@ -44,10 +80,29 @@ func (e *Events) Evict(l chan interface{}) {
 
 // Log broadcasts event to listeners. Each listener has 100 millisecond for
 // receiving event or it will be skipped.
-func (e *Events) Log(action, id, from string) {
+func (e *Events) Log(action, eventType string, actor eventtypes.Actor) {
+	now := time.Now().UTC()
+	jm := eventtypes.Message{
+		Action:   action,
+		Type:     eventType,
+		Actor:    actor,
+		Time:     now.Unix(),
+		TimeNano: now.UnixNano(),
+	}
+
+	// fill deprecated fields for container and images
+	switch eventType {
+	case eventtypes.ContainerEventType:
+		jm.ID = actor.ID
+		jm.Status = action
+		jm.From = actor.Attributes["image"]
+	case eventtypes.ImageEventType:
+		jm.ID = actor.ID
+		jm.Status = action
+	}
+
 	go func() {
 		e.mu.Lock()
-		jm := &jsonmessage.JSONMessage{Status: action, ID: id, From: from, Time: time.Now().UTC().Unix()}
 		if len(e.events) == cap(e.events) {
 			// discard oldest event
 			copy(e.events, e.events[1:])
@@ -55,8 +110,15 @@ func (e *Events) Log(action, id, from string) {
 		} else {
 			e.events = append(e.events, jm)
 		}
-		e.mu.Unlock()
+		if e.journal != nil {
+			if err := e.journal.Write(jm); err != nil {
+				logrus.Errorf("Error writing event to the journal: %v", err)
+			}
+		}
+		// publish while holding the lock, so that subscribers replaying
+		// past events never see an event twice
 		e.pub.Publish(jm)
+		e.mu.Unlock()
 	}()
 }
 
@@ -64,3 +126,16 @@ func (e *Events) Log(action, id, from string) {
 func (e *Events) SubscribersCount() int {
 	return e.pub.Len()
 }
+
+// Close closes the journal, if any. Events logged afterwards are only
+// kept in memory.
+func (e *Events) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.journal == nil {
+		return nil
+	}
+	err := e.journal.Close()
+	e.journal = nil
+	return err
+}
@@ -5,7 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/docker/docker/pkg/jsonmessage"
+	eventtypes "github.com/docker/docker/api/types/events"
 )
 
 func TestEventsLog(t *testing.T) {
@@ -18,10 +18,13 @@ func TestEventsLog(t *testing.T) {
 	if count != 2 {
 		t.Fatalf("Must be 2 subscribers, got %d", count)
 	}
-	e.Log("test", "cont", "image")
+	e.Log("test", eventtypes.ContainerEventType, eventtypes.Actor{
+		ID:         "cont",
+		Attributes: map[string]string{"image": "image"},
+	})
 	select {
 	case msg := <-l1:
-		jmsg, ok := msg.(*jsonmessage.JSONMessage)
+		jmsg, ok := msg.(eventtypes.Message)
 		if !ok {
 			t.Fatalf("Unexpected type %T", msg)
 		}
@@ -42,7 +45,7 @@ func TestEventsLog(t *testing.T) {
 	}
 	select {
 	case msg := <-l2:
-		jmsg, ok := msg.(*jsonmessage.JSONMessage)
+		jmsg, ok := msg.(eventtypes.Message)
 		if !ok {
 			t.Fatalf("Unexpected type %T", msg)
 		}
@@ -70,7 +73,10 @@ func TestEventsLogTimeout(t *testing.T) {
 
 	c := make(chan struct{})
 	go func() {
-		e.Log("test", "cont", "image")
+		e.Log("test", eventtypes.ContainerEventType, eventtypes.Actor{
+			ID:         "cont",
+			Attributes: map[string]string{"image": "image"},
+		})
 		close(c)
 	}()
 
@@ -88,7 +94,10 @@ func TestLogEvents(t *testing.T) {
 		action := fmt.Sprintf("action_%d", i)
 		id := fmt.Sprintf("cont_%d", i)
 		from := fmt.Sprintf("image_%d", i)
-		e.Log(action, id, from)
+		e.Log(action, eventtypes.ContainerEventType, eventtypes.Actor{
+			ID:         id,
+			Attributes: map[string]string{"image": from},
+		})
 	}
 	time.Sleep(50 * time.Millisecond)
 	current, l := e.Subscribe()
@@ -97,16 +106,19 @@ func TestLogEvents(t *testing.T) {
 		action := fmt.Sprintf("action_%d", num)
 		id := fmt.Sprintf("cont_%d", num)
 		from := fmt.Sprintf("image_%d", num)
-		e.Log(action, id, from)
+		e.Log(action, eventtypes.ContainerEventType, eventtypes.Actor{
+			ID:         id,
+			Attributes: map[string]string{"image": from},
+		})
 	}
 	if len(e.events) != eventsLimit {
 		t.Fatalf("Must be %d events, got %d", eventsLimit, len(e.events))
 	}
 
-	var msgs []*jsonmessage.JSONMessage
+	var msgs []eventtypes.Message
 	for len(msgs) < 10 {
 		m := <-l
-		jm, ok := (m).(*jsonmessage.JSONMessage)
+		jm, ok := (m).(eventtypes.Message)
 		if !ok {
 			t.Fatalf("Unexpected type %T", m)
 		}
@@ -133,3 +145,35 @@ func TestLogEvents(t *testing.T) {
 		t.Fatalf("Last action is %s, must be action_89", lastC.Status)
 	}
 }
+
+func TestLogEventsTyped(t *testing.T) {
+	e := New()
+	_, l := e.Subscribe()
+	defer e.Evict(l)
+
+	e.Log("create", eventtypes.VolumeEventType, eventtypes.Actor{
+		ID:         "myvolume",
+		Attributes: map[string]string{"driver": "local"},
+	})
+	select {
+	case msg := <-l:
+		jmsg, ok := msg.(eventtypes.Message)
+		if !ok {
+			t.Fatalf("Unexpected type %T", msg)
+		}
+		if jmsg.Type != eventtypes.VolumeEventType || jmsg.Action != "create" {
+			t.Fatalf("Expected a volume create event, got %s %s", jmsg.Type, jmsg.Action)
+		}
+		if jmsg.Actor.ID != "myvolume" || jmsg.Actor.Attributes["driver"] != "local" {
+			t.Fatalf("Unexpected actor %v", jmsg.Actor)
+		}
+		if jmsg.Status != "" || jmsg.ID != "" {
+			t.Fatalf("Deprecated fields must only be set for containers and images, got %s %s", jmsg.Status, jmsg.ID)
+		}
+		if jmsg.TimeNano == 0 || jmsg.TimeNano/int64(time.Second) != jmsg.Time {
+			t.Fatalf("Time %d does not match TimeNano %d", jmsg.Time, jmsg.TimeNano)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("Timeout waiting for broadcasted message")
+	}
+}
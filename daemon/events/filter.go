@@ -0,0 +1,111 @@
+package events
+
+import (
+	"strings"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/pkg/parsers"
+	"github.com/docker/docker/pkg/parsers/filters"
+)
+
+// Filter can filter out docker events from a stream
+type Filter struct {
+	filter filters.Args
+}
+
+// NewFilter creates a new Filter
+func NewFilter(filter filters.Args) *Filter {
+	return &Filter{filter: filter}
+}
+
+// Include returns true when the event ev is included by the filters
+func (ef *Filter) Include(ev eventtypes.Message) bool {
+	return ef.exactMatch("event", ev.Action) &&
+		ef.exactMatch("type", ev.Type) &&
+		ef.matchContainer(ev) &&
+		ef.matchVolume(ev) &&
+		ef.matchNetwork(ev) &&
+		ef.matchImage(ev) &&
+		ef.filter.MatchKVList("label", ev.Actor.Attributes)
+}
+
+func (ef *Filter) matchContainer(ev eventtypes.Message) bool {
+	return ef.fuzzyMatchName(ev, eventtypes.ContainerEventType)
+}
+
+func (ef *Filter) matchVolume(ev eventtypes.Message) bool {
+	return ef.fuzzyMatchName(ev, eventtypes.VolumeEventType)
+}
+
+func (ef *Filter) matchNetwork(ev eventtypes.Message) bool {
+	return ef.fuzzyMatchName(ev, eventtypes.NetworkEventType)
+}
+
+// fuzzyMatchName matches the filter named after the event type against
+// the actor ID, or a prefix of it, and the actor name.
+func (ef *Filter) fuzzyMatchName(ev eventtypes.Message, eventType string) bool {
+	values := ef.filter[eventType]
+	if len(values) == 0 {
+		return true
+	}
+	if ev.Type != eventType {
+		return false
+	}
+	name := ev.Actor.Attributes["name"]
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if strings.HasPrefix(ev.Actor.ID, v) || v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matchImage matches against both the image ID and the image name of image
+// events, and against the image a container was created from for container
+// events. Tags are optional, so `ubuntu` matches `ubuntu:latest`.
+func (ef *Filter) matchImage(ev eventtypes.Message) bool {
+	values := ef.filter["image"]
+	if len(values) == 0 {
+		return true
+	}
+
+	var candidates []string
+	switch ev.Type {
+	case eventtypes.ImageEventType:
+		candidates = []string{ev.Actor.ID, ev.Actor.Attributes["name"]}
+	case eventtypes.ContainerEventType:
+		candidates = []string{ev.Actor.Attributes["image"]}
+	default:
+		return false
+	}
+
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		for _, v := range values {
+			if repo, _ := parsers.ParseRepositoryTag(c); v == c || v == repo {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// exactMatch returns true if there are no values for the filter field, or
+// if one of them is equal to source.
+func (ef *Filter) exactMatch(field, source string) bool {
+	values := ef.filter[field]
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == source {
+			return true
+		}
+	}
+	return false
+}
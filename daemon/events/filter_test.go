@@ -0,0 +1,69 @@
+package events
+
+import (
+	"testing"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/pkg/parsers/filters"
+)
+
+func TestFilterInclude(t *testing.T) {
+	container := eventtypes.Message{
+		Type:   eventtypes.ContainerEventType,
+		Action: "start",
+		Actor: eventtypes.Actor{
+			ID: "4386fb97867d2c0c8a6b1a9bd3e6a4ab9c1e4eb6d20e3d3e8e8a3bdb5f1b1a2c",
+			Attributes: map[string]string{
+				"image":            "busybox:latest",
+				"name":             "foo",
+				"com.example.tier": "frontend",
+			},
+		},
+	}
+	image := eventtypes.Message{
+		Type:   eventtypes.ImageEventType,
+		Action: "tag",
+		Actor: eventtypes.Actor{
+			ID:         "busybox:latest",
+			Attributes: map[string]string{"name": "busybox:latest"},
+		},
+	}
+	volume := eventtypes.Message{
+		Type:   eventtypes.VolumeEventType,
+		Action: "create",
+		Actor: eventtypes.Actor{
+			ID:         "myvolume",
+			Attributes: map[string]string{"driver": "local"},
+		},
+	}
+
+	cases := []struct {
+		filter   filters.Args
+		included []bool // container, image, volume
+	}{
+		{filters.Args{}, []bool{true, true, true}},
+		{filters.Args{"type": {"container"}}, []bool{true, false, false}},
+		{filters.Args{"type": {"image", "volume"}}, []bool{false, true, true}},
+		{filters.Args{"event": {"start"}}, []bool{true, false, false}},
+		{filters.Args{"container": {"foo"}}, []bool{true, false, false}},
+		{filters.Args{"container": {"4386fb97867d"}}, []bool{true, false, false}},
+		{filters.Args{"container": {"bar"}}, []bool{false, false, false}},
+		{filters.Args{"image": {"busybox"}}, []bool{true, true, false}},
+		{filters.Args{"image": {"busybox:latest"}}, []bool{true, true, false}},
+		{filters.Args{"image": {"ubuntu"}}, []bool{false, false, false}},
+		{filters.Args{"volume": {"myvolume"}}, []bool{false, false, true}},
+		{filters.Args{"label": {"com.example.tier"}}, []bool{true, false, false}},
+		{filters.Args{"label": {"com.example.tier=frontend"}}, []bool{true, false, false}},
+		{filters.Args{"label": {"com.example.tier=backend"}}, []bool{false, false, false}},
+		{filters.Args{"type": {"container"}, "event": {"die"}}, []bool{false, false, false}},
+	}
+
+	for _, c := range cases {
+		f := NewFilter(c.filter)
+		for i, ev := range []eventtypes.Message{container, image, volume} {
+			if got := f.Include(ev); got != c.included[i] {
+				t.Errorf("filter %v on %s event: expected %v, got %v", c.filter, ev.Type, c.included[i], got)
+			}
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/pkg/system"
+)
+
+const (
+	// DefaultJournalMaxSize is the size a journal file can grow to before
+	// it is rotated.
+	DefaultJournalMaxSize = 10 * 1024 * 1024
+	// DefaultJournalMaxFiles is the number of journal files kept, including
+	// the one being written to.
+	DefaultJournalMaxFiles = 5
+
+	journalFileName = "events.log"
+)
+
+// Journal is an on-disk log of events. Events are stored one JSON message
+// per line. When the current file exceeds the maximum size it is rotated,
+// and only the most recent files are kept, so the journal never takes
+// more than about maxSize * maxFiles bytes.
+type Journal struct {
+	mu       sync.RWMutex
+	path     string
+	f        *os.File
+	size     int64
+	maxSize  int64
+	maxFiles int
+}
+
+// NewJournal opens the journal stored in root, creating it if it does not
+// exist yet. Events already recorded in the journal are kept.
+func NewJournal(root string, maxSize int64, maxFiles int) (*Journal, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultJournalMaxSize
+	}
+	if maxFiles < 1 {
+		maxFiles = DefaultJournalMaxFiles
+	}
+	if err := system.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+
+	j := &Journal{
+		path:     filepath.Join(root, journalFileName),
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// open opens the current journal file for appending. If the daemon did not
+// exit cleanly the last line may be incomplete: it is terminated so that
+// the next event starts on a line of its own.
+func (j *Journal) open() error {
+	f, err := os.OpenFile(j.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	size := fi.Size()
+	if size > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, size-1); err != nil {
+			f.Close()
+			return err
+		}
+		if last[0] != '\n' {
+			n, err := f.Write([]byte{'\n'})
+			if err != nil {
+				f.Close()
+				return err
+			}
+			size += int64(n)
+		}
+	}
+	j.f = f
+	j.size = size
+	return nil
+}
+
+// Write appends an event to the journal, rotating the journal files first
+// if the current one is full.
+func (j *Journal) Write(ev eventtypes.Message) error {
+	buf, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.f == nil {
+		return os.ErrInvalid
+	}
+	if j.size > 0 && j.size+int64(len(buf)) > j.maxSize {
+		if err := j.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := j.f.Write(buf)
+	j.size += int64(n)
+	return err
+}
+
+// rotate shifts the journal files by one, dropping the oldest, and starts
+// a new current file. The journal lock must be held.
+func (j *Journal) rotate() error {
+	if err := j.f.Close(); err != nil {
+		return err
+	}
+	j.f = nil
+
+	for i := j.maxFiles - 1; i > 0; i-- {
+		if err := os.Rename(j.fileName(i-1), j.fileName(i)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	j.f = f
+	j.size = 0
+	return nil
+}
+
+// fileName returns the name of the i-th journal file, 0 being the current
+// one and maxFiles-1 the oldest.
+func (j *Journal) fileName(i int) string {
+	if i == 0 {
+		return j.path
+	}
+	return j.path + "." + strconv.Itoa(i)
+}
+
+// Read returns the events recorded in the journal with a time between
+// since and until, in the order they were written. Both bounds are Unix
+// timestamps and inclusive; an until of zero or less means no upper bound.
+func (j *Journal) Read(since, until int64) ([]eventtypes.Message, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var events []eventtypes.Message
+	for i := j.maxFiles - 1; i >= 0; i-- {
+		f, err := os.Open(j.fileName(i))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		events, err = readJournalFile(f, events, since, until)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+func readJournalFile(r io.Reader, events []eventtypes.Message, since, until int64) ([]eventtypes.Message, error) {
+	rd := bufio.NewReader(r)
+	for {
+		line, err := rd.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var ev eventtypes.Message
+			if err := json.Unmarshal(line, &ev); err != nil {
+				logrus.Debugf("Skipping invalid event in journal: %v", err)
+			} else if inRange(ev, since, until) {
+				events = append(events, ev)
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				return events, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+// Close closes the current journal file. Events cannot be written to the
+// journal after it has been closed.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return nil
+	}
+	err := j.f.Close()
+	j.f = nil
+	return err
+}
+
+// inRange returns true if the event happened between since and until.
+func inRange(ev eventtypes.Message, since, until int64) bool {
+	if ev.Time < since {
+		return false
+	}
+	return until <= 0 || ev.Time <= until
+}
@@ -0,0 +1,255 @@
+package events
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+)
+
+func newTestJournal(t *testing.T, maxSize int64, maxFiles int) (*Journal, string) {
+	root, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := NewJournal(root, maxSize, maxFiles)
+	if err != nil {
+		os.RemoveAll(root)
+		t.Fatal(err)
+	}
+	return j, root
+}
+
+func testEvent(i int, t int64) eventtypes.Message {
+	return eventtypes.Message{
+		Type:   eventtypes.ContainerEventType,
+		Action: fmt.Sprintf("action_%d", i),
+		Actor:  eventtypes.Actor{ID: fmt.Sprintf("cont_%d", i)},
+		Time:   t,
+	}
+}
+
+func TestJournalReadSinceUntil(t *testing.T) {
+	j, root := newTestJournal(t, 0, 0)
+	defer os.RemoveAll(root)
+	defer j.Close()
+
+	for i := 0; i < 10; i++ {
+		if err := j.Write(testEvent(i, int64(100+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	evs, err := j.Read(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 10 {
+		t.Fatalf("Expected 10 events, got %d", len(evs))
+	}
+
+	evs, err = j.Read(103, 105)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 3 || evs[0].Action != "action_3" || evs[2].Action != "action_5" {
+		t.Fatalf("Expected action_3 to action_5, got %v", evs)
+	}
+}
+
+func TestJournalRotation(t *testing.T) {
+	// Every event of the test is about the same size, leave room for
+	// about 10 events per file.
+	j, root := newTestJournal(t, 1024, 3)
+	defer os.RemoveAll(root)
+	defer j.Close()
+
+	for i := 0; i < 100; i++ {
+		if err := j.Write(testEvent(i, int64(100+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(root, journalFileName+"*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 journal files, got %v", files)
+	}
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Size() > 1024 {
+			t.Fatalf("Journal file %s is larger than its maximum size: %d", f, fi.Size())
+		}
+	}
+
+	evs, err := j.Read(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) == 0 || len(evs) >= 100 {
+		t.Fatalf("Expected the oldest events to be dropped, got %d events", len(evs))
+	}
+	// The events left are the most recent ones, in order.
+	first := 100 - len(evs)
+	for i, ev := range evs {
+		if expected := fmt.Sprintf("action_%d", first+i); ev.Action != expected {
+			t.Fatalf("Expected %s at position %d, got %s", expected, i, ev.Action)
+		}
+	}
+}
+
+func TestJournalReopen(t *testing.T) {
+	j, root := newTestJournal(t, 0, 0)
+	defer os.RemoveAll(root)
+
+	for i := 0; i < 5; i++ {
+		if err := j.Write(testEvent(i, int64(100+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate an event that was only partially written before a crash.
+	f, err := os.OpenFile(filepath.Join(root, journalFileName), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte(`{"Type":"container","Act`))
+	f.Close()
+
+	j, err = NewJournal(root, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if err := j.Write(testEvent(5, 105)); err != nil {
+		t.Fatal(err)
+	}
+
+	evs, err := j.Read(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 6 {
+		t.Fatalf("Expected 6 events, got %d: %v", len(evs), evs)
+	}
+	if evs[5].Action != "action_5" {
+		t.Fatalf("Expected action_5 to be the last event, got %s", evs[5].Action)
+	}
+}
+
+func TestJournalConcurrentReaders(t *testing.T) {
+	j, root := newTestJournal(t, 2048, 4)
+	defer os.RemoveAll(root)
+	defer j.Close()
+
+	const total = 200
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := 0; i < total; i++ {
+			if err := j.Write(testEvent(i, int64(i))); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				evs, err := j.Read(0, 0)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				// Every read sees a contiguous sequence of events,
+				// even while files are being rotated.
+				for i := 1; i < len(evs); i++ {
+					if evs[i].Time != evs[i-1].Time+1 {
+						t.Errorf("Non contiguous events in journal: %d after %d", evs[i].Time, evs[i-1].Time)
+						return
+					}
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestEventsSubscribeRangeJournal(t *testing.T) {
+	j, root := newTestJournal(t, 0, 0)
+	defer os.RemoveAll(root)
+
+	e := NewWithJournal(j)
+	for i := 0; i < eventsLimit+16; i++ {
+		e.Log(fmt.Sprintf("action_%d", i), eventtypes.ContainerEventType, eventtypes.Actor{ID: "cont"})
+		// Log is asynchronous; keep the events in order.
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	current, l, err := e.SubscribeRange(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Evict(l)
+	if len(current) != eventsLimit+16 {
+		t.Fatalf("Expected %d events from the journal, got %d", eventsLimit+16, len(current))
+	}
+	if current[0].Action != "action_0" {
+		t.Fatalf("First action is %s, must be action_0", current[0].Action)
+	}
+
+	e.Log("live", eventtypes.ContainerEventType, eventtypes.Actor{ID: "cont"})
+	select {
+	case msg := <-l:
+		if ev := msg.(eventtypes.Message); ev.Action != "live" {
+			t.Fatalf("Expected the live event, got %s", ev.Action)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for broadcasted message")
+	}
+
+	// Events survive a restart of the events service.
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	j, err = NewJournal(root, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e = NewWithJournal(j)
+	defer e.Close()
+	current, l, err = e.SubscribeRange(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Evict(l)
+	if len(current) != eventsLimit+17 {
+		t.Fatalf("Expected %d events after reopening the journal, got %d", eventsLimit+17, len(current))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	// TODO Windows: Factor out ulimit
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/ulimit"
 	"github.com/opencontainers/runc/libcontainer"
 	"github.com/opencontainers/runc/libcontainer/configs"
@@ -89,6 +90,23 @@ type Driver interface {
 
 	// Stats returns resource stats for a running container
 	Stats(id string) (*ResourceStats, error)
+
+	// Update updates the resources of a running container with the ones
+	// of the command.
+	Update(c *Command) error
+
+	// Checkpoint saves the state of the processes of a running container
+	// to disk. The container is stopped unless opts.LeaveRunning is set.
+	Checkpoint(c *Command, opts *libcontainer.CriuOpts) error
+
+	// Restore restores the processes of a container from a checkpoint,
+	// blocks until the restored process exits and returns the exit code.
+	Restore(c *Command, pipes *Pipes, restoreCallback StartCallback, opts *libcontainer.CriuOpts) (ExitStatus, error)
+
+	// Reattach attaches to the process of a container started with
+	// LiveRestore by a previous instance of the daemon, blocks until the
+	// process exits and returns the exit code.
+	Reattach(c *Command, pipes *Pipes, reattachCallback StartCallback) (ExitStatus, error)
 }
 
 // Network settings of the container
@@ -148,17 +166,22 @@ type NetworkInterface struct {
 // Currently these are all for cgroup configs.
 // TODO Windows: Factor out ulimit.Rlimit
 type Resources struct {
-	Memory           int64            `json:"memory"`
-	MemorySwap       int64            `json:"memory_swap"`
-	CPUShares        int64            `json:"cpu_shares"`
-	CpusetCpus       string           `json:"cpuset_cpus"`
-	CpusetMems       string           `json:"cpuset_mems"`
-	CPUPeriod        int64            `json:"cpu_period"`
-	CPUQuota         int64            `json:"cpu_quota"`
-	BlkioWeight      int64            `json:"blkio_weight"`
-	Rlimits          []*ulimit.Rlimit `json:"rlimits"`
-	OomKillDisable   bool             `json:"oom_kill_disable"`
-	MemorySwappiness int64            `json:"memory_swappiness"`
+	Memory                       int64            `json:"memory"`
+	MemorySwap                   int64            `json:"memory_swap"`
+	CPUShares                    int64            `json:"cpu_shares"`
+	CpusetCpus                   string           `json:"cpuset_cpus"`
+	CpusetMems                   string           `json:"cpuset_mems"`
+	CPUPeriod                    int64            `json:"cpu_period"`
+	CPUQuota                     int64            `json:"cpu_quota"`
+	BlkioWeight                  int64            `json:"blkio_weight"`
+	BlkioThrottleReadBpsDevice   []string         `json:"blkio_throttle_read_bps_device"`
+	BlkioThrottleWriteBpsDevice  []string         `json:"blkio_throttle_write_bps_device"`
+	BlkioThrottleReadIOpsDevice  []string         `json:"blkio_throttle_read_iops_device"`
+	BlkioThrottleWriteIOpsDevice []string         `json:"blkio_throttle_write_iops_device"`
+	Rlimits                      []*ulimit.Rlimit `json:"rlimits"`
+	OomKillDisable               bool             `json:"oom_kill_disable"`
+	MemorySwappiness             int64            `json:"memory_swappiness"`
+	PidsLimit                    int64            `json:"pids_limit"`
 }
 
 // ResourceStats contains information about resource usage by a container.
@@ -220,8 +243,12 @@ type Command struct {
 	MountLabel         string            `json:"mount_label"`
 	LxcConfig          []string          `json:"lxc_config"`
 	AppArmorProfile    string            `json:"apparmor_profile"`
-	CgroupParent       string            `json:"cgroup_parent"` // The parent cgroup for this command.
+	SeccompProfile     string            `json:"seccomp_profile"` // "unconfined", a JSON profile, or empty for the default profile
+	CgroupParent       string            `json:"cgroup_parent"`   // The parent cgroup for this command.
 	FirstStart         bool              `json:"first_start"`
 	LayerPaths         []string          `json:"layer_paths"` // Windows needs to know the layer paths and folder for a command
 	LayerFolder        string            `json:"layer_folder"`
+	UIDMapping         []idtools.IDMap   `json:"uidmapping"` // user namespace ID mappings, nil when the root is not remapped
+	GIDMapping         []idtools.IDMap   `json:"gidmapping"`
+	LiveRestore        bool              `json:"live_restore"` // keep the process running when the daemon exits
 }
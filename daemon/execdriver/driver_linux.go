@@ -61,8 +61,13 @@ func SetupCgroups(container *configs.Config, c *Command) error {
 		container.Cgroups.CpuPeriod = c.Resources.CPUPeriod
 		container.Cgroups.CpuQuota = c.Resources.CPUQuota
 		container.Cgroups.BlkioWeight = c.Resources.BlkioWeight
+		container.Cgroups.BlkioThrottleReadBpsDevice = strings.Join(c.Resources.BlkioThrottleReadBpsDevice, "\n")
+		container.Cgroups.BlkioThrottleWriteBpsDevice = strings.Join(c.Resources.BlkioThrottleWriteBpsDevice, "\n")
+		container.Cgroups.BlkioThrottleReadIOpsDevice = strings.Join(c.Resources.BlkioThrottleReadIOpsDevice, "\n")
+		container.Cgroups.BlkioThrottleWriteIOpsDevice = strings.Join(c.Resources.BlkioThrottleWriteIOpsDevice, "\n")
 		container.Cgroups.OomKillDisable = c.Resources.OomKillDisable
 		container.Cgroups.MemorySwappiness = c.Resources.MemorySwappiness
+		container.Cgroups.PidsLimit = c.Resources.PidsLimit
 	}
 
 	return nil
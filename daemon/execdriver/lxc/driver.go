@@ -40,6 +40,14 @@ const DriverName = "lxc"
 // ErrExec defines unsupported error message
 var ErrExec = errors.New("Unsupported: Exec is not supported by the lxc driver")
 
+// ErrCheckpoint defines an error for checkpoint and restore, which are not
+// supported by the lxc driver.
+var ErrCheckpoint = errors.New("Unsupported: Checkpoint and restore are not supported by the lxc driver")
+
+// ErrLiveRestore defines an error for live restore, which is not supported
+// by the lxc driver.
+var ErrLiveRestore = errors.New("Unsupported: Live restore is not supported by the lxc driver")
+
 // Driver contains all information for lxc driver,
 // it implements execdriver.Driver
 type Driver struct {
@@ -874,6 +882,49 @@ func (d *Driver) Exec(c *execdriver.Command, processConfig *execdriver.ProcessCo
 	return -1, ErrExec
 }
 
+// Update implements the exec driver Driver interface,
+// it executes lxc-cgroup to write the resources of the command to the cgroups
+// of the container.
+func (d *Driver) Update(c *execdriver.Command) error {
+	if _, ok := d.activeContainers[c.ID]; !ok {
+		return execdriver.ErrNotRunning
+	}
+	r := c.Resources
+	var values [][2]string
+	if r.CPUShares != 0 {
+		values = append(values, [2]string{"cpu.shares", strconv.FormatInt(r.CPUShares, 10)})
+	}
+	if r.CPUPeriod != 0 {
+		values = append(values, [2]string{"cpu.cfs_period_us", strconv.FormatInt(r.CPUPeriod, 10)})
+	}
+	if r.CPUQuota != 0 {
+		values = append(values, [2]string{"cpu.cfs_quota_us", strconv.FormatInt(r.CPUQuota, 10)})
+	}
+	if r.CpusetCpus != "" {
+		values = append(values, [2]string{"cpuset.cpus", r.CpusetCpus})
+	}
+	if r.CpusetMems != "" {
+		values = append(values, [2]string{"cpuset.mems", r.CpusetMems})
+	}
+	if r.BlkioWeight != 0 {
+		values = append(values, [2]string{"blkio.weight", strconv.FormatInt(r.BlkioWeight, 10)})
+	}
+	if r.Memory != 0 {
+		memory := strconv.FormatInt(r.Memory, 10)
+		values = append(values, [2]string{"memory.limit_in_bytes", memory}, [2]string{"memory.soft_limit_in_bytes", memory})
+		if memSwap := getMemorySwap(r); memSwap > 0 {
+			values = append(values, [2]string{"memory.memsw.limit_in_bytes", strconv.FormatInt(memSwap, 10)})
+		}
+	}
+	for _, v := range values {
+		output, err := exec.Command("lxc-cgroup", "-n", c.ID, v[0], v[1]).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("Err: %s Output: %s", err, output)
+		}
+	}
+	return nil
+}
+
 // Stats implements the exec driver Driver interface.
 // Lxc doesn't implement it's own Stats, it does some trick by implementing
 // execdriver.Stats to get stats info by libcontainer APIs.
@@ -883,3 +934,21 @@ func (d *Driver) Stats(id string) (*execdriver.ResourceStats, error) {
 	}
 	return execdriver.Stats(d.containerDir(id), d.activeContainers[id].container.Cgroups.Memory, d.machineMemory)
 }
+
+// Checkpoint implements the exec driver Driver interface,
+// it is not implemented by lxc.
+func (d *Driver) Checkpoint(c *execdriver.Command, opts *libcontainer.CriuOpts) error {
+	return ErrCheckpoint
+}
+
+// Restore implements the exec driver Driver interface,
+// it is not implemented by lxc.
+func (d *Driver) Restore(c *execdriver.Command, pipes *execdriver.Pipes, restoreCallback execdriver.StartCallback, opts *libcontainer.CriuOpts) (execdriver.ExitStatus, error) {
+	return execdriver.ExitStatus{ExitCode: -1}, ErrCheckpoint
+}
+
+// Reattach implements the exec driver Driver interface,
+// it is not implemented by lxc.
+func (d *Driver) Reattach(c *execdriver.Command, pipes *execdriver.Pipes, reattachCallback execdriver.StartCallback) (execdriver.ExitStatus, error) {
+	return execdriver.ExitStatus{ExitCode: -1}, ErrLiveRestore
+}
@@ -114,12 +114,27 @@ lxc.cgroup.cpu.cfs_quota_us = {{.Resources.CPUQuota}}
 {{if .Resources.BlkioWeight}}
 lxc.cgroup.blkio.weight = {{.Resources.BlkioWeight}}
 {{end}}
+{{range $value := .Resources.BlkioThrottleReadBpsDevice}}
+lxc.cgroup.blkio.throttle.read_bps_device = {{$value}}
+{{end}}
+{{range $value := .Resources.BlkioThrottleWriteBpsDevice}}
+lxc.cgroup.blkio.throttle.write_bps_device = {{$value}}
+{{end}}
+{{range $value := .Resources.BlkioThrottleReadIOpsDevice}}
+lxc.cgroup.blkio.throttle.read_iops_device = {{$value}}
+{{end}}
+{{range $value := .Resources.BlkioThrottleWriteIOpsDevice}}
+lxc.cgroup.blkio.throttle.write_iops_device = {{$value}}
+{{end}}
 {{if .Resources.OomKillDisable}}
 lxc.cgroup.memory.oom_control = {{.Resources.OomKillDisable}}
 {{end}}
 {{if gt .Resources.MemorySwappiness 0}}
 lxc.cgroup.memory.swappiness = {{.Resources.MemorySwappiness}}
 {{end}}
+{{if gt .Resources.PidsLimit 0}}
+lxc.cgroup.pids.max = {{.Resources.PidsLimit}}
+{{end}}
 {{end}}
 
 {{if .LxcConfig}}
@@ -0,0 +1,67 @@
+// +build linux,cgo
+
+package native
+
+import (
+	"os"
+
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/opencontainers/runc/libcontainer"
+)
+
+// Checkpoint implements the exec driver Driver interface,
+// it dumps the processes of the container with CRIU.
+func (d *Driver) Checkpoint(c *execdriver.Command, opts *libcontainer.CriuOpts) error {
+	d.Lock()
+	active := d.activeContainers[c.ID]
+	d.Unlock()
+	if active == nil {
+		return execdriver.ErrNotRunning
+	}
+	return active.Checkpoint(opts)
+}
+
+// Restore implements the exec driver Driver interface,
+// it restores the processes of the container with CRIU and waits for the
+// restored main process to exit.
+func (d *Driver) Restore(c *execdriver.Command, pipes *execdriver.Pipes, restoreCallback execdriver.StartCallback, opts *libcontainer.CriuOpts) (execdriver.ExitStatus, error) {
+	container, err := d.createContainer(c)
+	if err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+
+	// the arguments of the process are not used, the restored process is
+	// the one saved in the checkpoint.
+	p := &libcontainer.Process{}
+	if err := setupPipes(container, &c.ProcessConfig, p, pipes); err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+
+	cont, err := d.factory.Create(c.ID, container)
+	if err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	d.Lock()
+	d.activeContainers[c.ID] = cont
+	d.Unlock()
+	defer func() {
+		cont.Destroy()
+		d.cleanContainer(c.ID)
+	}()
+
+	if err := cont.Restore(p, opts); err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+
+	if restoreCallback != nil {
+		pid, err := p.Pid()
+		if err != nil {
+			p.Signal(os.Kill)
+			p.Wait()
+			return execdriver.ExitStatus{ExitCode: -1}, err
+		}
+		restoreCallback(&c.ProcessConfig, pid)
+	}
+
+	return waitForExit(cont, p)
+}
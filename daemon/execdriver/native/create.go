@@ -33,6 +33,10 @@ func (d *Driver) createContainer(c *execdriver.Command) (*configs.Config, error)
 		return nil, err
 	}
 
+	if err := d.createUser(container, c); err != nil {
+		return nil, err
+	}
+
 	if err := d.createNetwork(container, c); err != nil {
 		return nil, err
 	}
@@ -71,6 +75,10 @@ func (d *Driver) createContainer(c *execdriver.Command) (*configs.Config, error)
 		container.AppArmorProfile = c.AppArmorProfile
 	}
 
+	if err := d.setupSeccomp(container, c); err != nil {
+		return nil, err
+	}
+
 	if err := execdriver.SetupCgroups(container, c); err != nil {
 		return nil, err
 	}
@@ -187,6 +195,30 @@ func (d *Driver) createUTS(container *configs.Config, c *execdriver.Command) err
 	return nil
 }
 
+func (d *Driver) createUser(container *configs.Config, c *execdriver.Command) error {
+	// only enable a user namespace when the daemon has a remapped root
+	if len(c.UIDMapping) == 0 {
+		return nil
+	}
+
+	container.Namespaces.Add(configs.NEWUSER, "")
+	for _, m := range c.UIDMapping {
+		container.UidMappings = append(container.UidMappings, configs.IDMap{
+			ContainerID: m.ContainerID,
+			HostID:      m.HostID,
+			Size:        m.Size,
+		})
+	}
+	for _, m := range c.GIDMapping {
+		container.GidMappings = append(container.GidMappings, configs.IDMap{
+			ContainerID: m.ContainerID,
+			HostID:      m.HostID,
+			Size:        m.Size,
+		})
+	}
+	return nil
+}
+
 func (d *Driver) setPrivileged(container *configs.Config) (err error) {
 	container.Capabilities = execdriver.GetAllCapabilities()
 	container.Cgroups.AllowAllDevices = true
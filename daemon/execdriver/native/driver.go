@@ -42,6 +42,7 @@ type Driver struct {
 	activeContainers map[string]libcontainer.Container
 	machineMemory    int64
 	factory          libcontainer.Factory
+	cgroupDriver     string
 	sync.Mutex
 }
 
@@ -73,9 +74,9 @@ func NewDriver(root, initPath string, options []string) (*Driver, error) {
 	// choose cgroup manager
 	// this makes sure there are no breaking changes to people
 	// who upgrade from versions without native.cgroupdriver opt
-	cgm := libcontainer.Cgroupfs
+	cgm := "cgroupfs"
 	if systemd.UseSystemd() {
-		cgm = libcontainer.SystemdCgroups
+		cgm = "systemd"
 	}
 
 	// parse the options
@@ -91,13 +92,13 @@ func NewDriver(root, initPath string, options []string) (*Driver, error) {
 			switch val {
 			case "systemd":
 				if systemd.UseSystemd() {
-					cgm = libcontainer.SystemdCgroups
+					cgm = "systemd"
 				} else {
 					// warn them that they chose the wrong driver
 					logrus.Warn("You cannot use systemd as native.cgroupdriver, using cgroupfs instead")
 				}
 			case "cgroupfs":
-				cgm = libcontainer.Cgroupfs
+				cgm = "cgroupfs"
 			default:
 				return nil, fmt.Errorf("Unknown native.cgroupdriver given %q. try cgroupfs or systemd", val)
 			}
@@ -108,7 +109,7 @@ func NewDriver(root, initPath string, options []string) (*Driver, error) {
 
 	f, err := libcontainer.New(
 		root,
-		cgm,
+		cgroupManager(cgm),
 		libcontainer.InitPath(reexec.Self(), DriverName),
 	)
 	if err != nil {
@@ -121,6 +122,7 @@ func NewDriver(root, initPath string, options []string) (*Driver, error) {
 		activeContainers: make(map[string]libcontainer.Container),
 		machineMemory:    meminfo.MemTotal,
 		factory:          f,
+		cgroupDriver:     cgm,
 	}, nil
 }
 
@@ -132,6 +134,10 @@ type execOutput struct {
 // Run implements the exec driver Driver interface,
 // it calls libcontainer APIs to run a container.
 func (d *Driver) Run(c *execdriver.Command, pipes *execdriver.Pipes, startCallback execdriver.StartCallback) (execdriver.ExitStatus, error) {
+	if c.LiveRestore {
+		return d.runShim(c, pipes, startCallback)
+	}
+
 	// take the Command and populate the libcontainer.Config from it
 	container, err := d.createContainer(c)
 	if err != nil {
@@ -175,6 +181,12 @@ func (d *Driver) Run(c *execdriver.Command, pipes *execdriver.Pipes, startCallba
 		startCallback(&c.ProcessConfig, pid)
 	}
 
+	return waitForExit(cont, p)
+}
+
+// waitForExit waits for the main process of the container to exit and
+// returns its exit status.
+func waitForExit(cont libcontainer.Container, p *libcontainer.Process) (execdriver.ExitStatus, error) {
 	oom := notifyOnOOM(cont)
 	waitF := p.Wait
 	if nss := cont.Config().Namespaces; !nss.Contains(configs.NEWPID) {
@@ -351,6 +363,9 @@ func (d *Driver) cleanContainer(id string) error {
 	d.Lock()
 	delete(d.activeContainers, id)
 	d.Unlock()
+	if err := os.RemoveAll(d.shimDir(id)); err != nil {
+		return err
+	}
 	return os.RemoveAll(filepath.Join(d.root, id))
 }
 
@@ -389,6 +404,34 @@ func (d *Driver) Stats(id string) (*execdriver.ResourceStats, error) {
 	}, nil
 }
 
+// Update implements the exec driver Driver interface,
+// it writes the resources of the command to the cgroups of the container.
+func (d *Driver) Update(c *execdriver.Command) error {
+	d.Lock()
+	active := d.activeContainers[c.ID]
+	d.Unlock()
+	if active == nil {
+		return execdriver.ErrNotRunning
+	}
+
+	config := active.Config()
+	if c.Resources.MemorySwap > config.Cgroups.MemorySwap && config.Cgroups.MemorySwap > 0 {
+		// The memory limit can not be raised above the memory+swap limit,
+		// so the latter is raised first.
+		cgroups := *config.Cgroups
+		cgroups.MemorySwap = c.Resources.MemorySwap
+		raised := config
+		raised.Cgroups = &cgroups
+		if err := active.Set(raised); err != nil {
+			return err
+		}
+	}
+	if err := execdriver.SetupCgroups(&config, c); err != nil {
+		return err
+	}
+	return active.Set(config)
+}
+
 // TtyConsole implements the exec driver Terminal interface.
 type TtyConsole struct {
 	console libcontainer.Console
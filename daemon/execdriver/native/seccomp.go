@@ -0,0 +1,144 @@
+// +build linux,cgo
+
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"syscall"
+
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+// seccompProfile is the JSON format of the profiles given with
+// --security-opt seccomp=<profile.json>.
+type seccompProfile struct {
+	DefaultAction string            `json:"defaultAction"`
+	Syscalls      []*seccompSyscall `json:"syscalls"`
+}
+
+// seccompSyscall is the rule applied to a single syscall. When Args are
+// given, the action is only taken when one of the conditions matches.
+type seccompSyscall struct {
+	Name   string        `json:"name"`
+	Action string        `json:"action"`
+	Args   []*seccompArg `json:"args"`
+}
+
+// seccompArg is a condition on an argument of a syscall.
+type seccompArg struct {
+	Index int    `json:"index"`
+	Value uint32 `json:"value"`
+	Op    string `json:"op"`
+}
+
+var seccompActions = map[string]configs.Action{
+	"SCMP_ACT_KILL":  configs.Kill,
+	"SCMP_ACT_TRAP":  configs.Trap,
+	"SCMP_ACT_ERRNO": configs.Action(syscall.EPERM),
+	"SCMP_ACT_ALLOW": configs.Allow,
+}
+
+var seccompOperators = map[string]configs.Operator{
+	"SCMP_CMP_EQ":        configs.EqualTo,
+	"SCMP_CMP_NE":        configs.NotEqualTo,
+	"SCMP_CMP_GT":        configs.GreatherThan,
+	"SCMP_CMP_LT":        configs.LessThan,
+	"SCMP_CMP_MASKED_EQ": configs.MaskEqualTo,
+}
+
+func (d *Driver) setupSeccomp(container *configs.Config, c *execdriver.Command) error {
+	if c.ProcessConfig.Privileged || c.SeccompProfile == "unconfined" {
+		return nil
+	}
+	if syscallNumbers == nil {
+		if c.SeccompProfile == "" {
+			// the default profile is only applied where it is supported
+			return nil
+		}
+		return fmt.Errorf("seccomp profiles are not supported on %s", runtime.GOARCH)
+	}
+
+	profile := defaultSeccompProfile
+	if c.SeccompProfile != "" {
+		profile = &seccompProfile{}
+		if err := json.Unmarshal([]byte(c.SeccompProfile), profile); err != nil {
+			return fmt.Errorf("Decoding seccomp profile failed: %v", err)
+		}
+	}
+
+	seccomp, err := profile.config()
+	if err != nil {
+		return err
+	}
+	container.Seccomp = seccomp
+	return nil
+}
+
+// config translates the profile into the libcontainer configuration. The
+// filter of libcontainer allows the syscalls it does not list, so a default
+// action other than SCMP_ACT_ALLOW is applied by listing every known syscall
+// the profile does not mention.
+func (p *seccompProfile) config() (*configs.Seccomp, error) {
+	defaultAction, ok := seccompActions[p.DefaultAction]
+	if !ok {
+		return nil, fmt.Errorf("Invalid seccomp default action: %q", p.DefaultAction)
+	}
+
+	var (
+		seccomp = &configs.Seccomp{}
+		listed  = make(map[string]bool)
+	)
+	for _, s := range p.Syscalls {
+		nr, ok := syscallNumbers[s.Name]
+		if !ok {
+			return nil, fmt.Errorf("Unknown syscall in seccomp profile: %q", s.Name)
+		}
+		if listed[s.Name] {
+			return nil, fmt.Errorf("Syscall %s is listed more than once in the seccomp profile", s.Name)
+		}
+		listed[s.Name] = true
+
+		action, ok := seccompActions[s.Action]
+		if !ok {
+			return nil, fmt.Errorf("Invalid seccomp action for %s: %q", s.Name, s.Action)
+		}
+		call := &configs.Syscall{Value: nr, Action: action}
+		if len(s.Args) > 0 {
+			// Calls whose arguments do not match are always allowed.
+			if action == configs.Allow || defaultAction != configs.Allow {
+				return nil, fmt.Errorf("Argument conditions on %s are only supported to restrict a syscall with the SCMP_ACT_ALLOW default action", s.Name)
+			}
+			for _, a := range s.Args {
+				op, ok := seccompOperators[a.Op]
+				if !ok {
+					return nil, fmt.Errorf("Invalid seccomp operator for %s: %q", s.Name, a.Op)
+				}
+				if a.Index < 0 || a.Index > 5 || a.Index != s.Args[0].Index {
+					return nil, fmt.Errorf("Argument conditions on %s must all apply to the same argument, from 0 to 5", s.Name)
+				}
+				call.Args = append(call.Args, &configs.Arg{Index: a.Index, Value: a.Value, Op: op})
+			}
+		}
+		seccomp.Syscalls = append(seccomp.Syscalls, call)
+	}
+
+	if defaultAction != configs.Allow {
+		for name, nr := range syscallNumbers {
+			if !listed[name] {
+				seccomp.Syscalls = append(seccomp.Syscalls, &configs.Syscall{Value: nr, Action: defaultAction})
+			}
+		}
+	}
+	sort.Sort(byValue(seccomp.Syscalls))
+	return seccomp, nil
+}
+
+type byValue []*configs.Syscall
+
+func (s byValue) Len() int           { return len(s) }
+func (s byValue) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byValue) Less(i, j int) bool { return s[i].Value < s[j].Value }
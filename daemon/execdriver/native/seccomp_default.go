@@ -0,0 +1,57 @@
+// +build linux,cgo
+
+package native
+
+// defaultSeccompProfile is applied to the containers that are not privileged
+// and were not given a profile. It denies the syscalls that can affect the
+// host or the kernel outside of the namespaces of the container.
+var defaultSeccompProfile = &seccompProfile{
+	DefaultAction: "SCMP_ACT_ALLOW",
+	Syscalls: []*seccompSyscall{
+		{Name: "acct", Action: "SCMP_ACT_ERRNO"},
+		{Name: "add_key", Action: "SCMP_ACT_ERRNO"},
+		{Name: "bpf", Action: "SCMP_ACT_ERRNO"},
+		{Name: "clock_adjtime", Action: "SCMP_ACT_ERRNO"},
+		{Name: "clock_settime", Action: "SCMP_ACT_ERRNO"},
+		{Name: "create_module", Action: "SCMP_ACT_ERRNO"},
+		{Name: "delete_module", Action: "SCMP_ACT_ERRNO"},
+		{Name: "finit_module", Action: "SCMP_ACT_ERRNO"},
+		{Name: "get_kernel_syms", Action: "SCMP_ACT_ERRNO"},
+		{Name: "get_mempolicy", Action: "SCMP_ACT_ERRNO"},
+		{Name: "init_module", Action: "SCMP_ACT_ERRNO"},
+		{Name: "ioperm", Action: "SCMP_ACT_ERRNO"},
+		{Name: "iopl", Action: "SCMP_ACT_ERRNO"},
+		{Name: "kcmp", Action: "SCMP_ACT_ERRNO"},
+		{Name: "kexec_file_load", Action: "SCMP_ACT_ERRNO"},
+		{Name: "kexec_load", Action: "SCMP_ACT_ERRNO"},
+		{Name: "keyctl", Action: "SCMP_ACT_ERRNO"},
+		{Name: "lookup_dcookie", Action: "SCMP_ACT_ERRNO"},
+		{Name: "mbind", Action: "SCMP_ACT_ERRNO"},
+		{Name: "mount", Action: "SCMP_ACT_ERRNO"},
+		{Name: "move_pages", Action: "SCMP_ACT_ERRNO"},
+		{Name: "name_to_handle_at", Action: "SCMP_ACT_ERRNO"},
+		{Name: "nfsservctl", Action: "SCMP_ACT_ERRNO"},
+		{Name: "open_by_handle_at", Action: "SCMP_ACT_ERRNO"},
+		{Name: "perf_event_open", Action: "SCMP_ACT_ERRNO"},
+		{Name: "pivot_root", Action: "SCMP_ACT_ERRNO"},
+		{Name: "process_vm_readv", Action: "SCMP_ACT_ERRNO"},
+		{Name: "process_vm_writev", Action: "SCMP_ACT_ERRNO"},
+		{Name: "ptrace", Action: "SCMP_ACT_ERRNO"},
+		{Name: "query_module", Action: "SCMP_ACT_ERRNO"},
+		{Name: "quotactl", Action: "SCMP_ACT_ERRNO"},
+		{Name: "reboot", Action: "SCMP_ACT_ERRNO"},
+		{Name: "request_key", Action: "SCMP_ACT_ERRNO"},
+		{Name: "set_mempolicy", Action: "SCMP_ACT_ERRNO"},
+		{Name: "setns", Action: "SCMP_ACT_ERRNO"},
+		{Name: "settimeofday", Action: "SCMP_ACT_ERRNO"},
+		{Name: "swapoff", Action: "SCMP_ACT_ERRNO"},
+		{Name: "swapon", Action: "SCMP_ACT_ERRNO"},
+		{Name: "sysfs", Action: "SCMP_ACT_ERRNO"},
+		{Name: "_sysctl", Action: "SCMP_ACT_ERRNO"},
+		{Name: "umount2", Action: "SCMP_ACT_ERRNO"},
+		{Name: "unshare", Action: "SCMP_ACT_ERRNO"},
+		{Name: "uselib", Action: "SCMP_ACT_ERRNO"},
+		{Name: "userfaultfd", Action: "SCMP_ACT_ERRNO"},
+		{Name: "ustat", Action: "SCMP_ACT_ERRNO"},
+	},
+}
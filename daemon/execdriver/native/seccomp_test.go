@@ -0,0 +1,115 @@
+// +build linux,cgo,amd64
+
+package native
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+func TestSetupSeccompDefault(t *testing.T) {
+	d := &Driver{}
+	container := &configs.Config{}
+	if err := d.setupSeccomp(container, &execdriver.Command{}); err != nil {
+		t.Fatal(err)
+	}
+	if container.Seccomp == nil || len(container.Seccomp.Syscalls) != len(defaultSeccompProfile.Syscalls) {
+		t.Fatalf("Expected the default profile to be applied, got %v", container.Seccomp)
+	}
+	for _, s := range container.Seccomp.Syscalls {
+		if s.Value == syscall.SYS_MOUNT && s.Action != configs.Action(syscall.EPERM) {
+			t.Fatalf("Expected mount to fail with EPERM, got action %d", s.Action)
+		}
+	}
+
+	for _, c := range []*execdriver.Command{
+		{SeccompProfile: "unconfined"},
+		{ProcessConfig: execdriver.ProcessConfig{Privileged: true}},
+	} {
+		container := &configs.Config{}
+		if err := d.setupSeccomp(container, c); err != nil {
+			t.Fatal(err)
+		}
+		if container.Seccomp != nil {
+			t.Fatalf("Expected no seccomp filter, got %v", container.Seccomp)
+		}
+	}
+}
+
+func TestSetupSeccompProfile(t *testing.T) {
+	d := &Driver{}
+	container := &configs.Config{}
+	c := &execdriver.Command{SeccompProfile: `{
+		"defaultAction": "SCMP_ACT_ERRNO",
+		"syscalls": [
+			{"name": "read", "action": "SCMP_ACT_ALLOW"},
+			{"name": "kill", "action": "SCMP_ACT_KILL"}
+		]
+	}`}
+	if err := d.setupSeccomp(container, c); err != nil {
+		t.Fatal(err)
+	}
+	if len(container.Seccomp.Syscalls) != len(syscallNumbers) {
+		t.Fatalf("Expected every syscall to be listed, got %d", len(container.Seccomp.Syscalls))
+	}
+	for _, s := range container.Seccomp.Syscalls {
+		switch s.Value {
+		case syscall.SYS_READ:
+			if s.Action != configs.Allow {
+				t.Fatalf("Expected read to be allowed, got action %d", s.Action)
+			}
+		case syscall.SYS_KILL:
+			if s.Action != configs.Kill {
+				t.Fatalf("Expected kill to be killed, got action %d", s.Action)
+			}
+		default:
+			if s.Action != configs.Action(syscall.EPERM) {
+				t.Fatalf("Expected syscall %d to fail with EPERM, got action %d", s.Value, s.Action)
+			}
+		}
+	}
+}
+
+func TestSeccompProfileArgs(t *testing.T) {
+	p := &seccompProfile{
+		DefaultAction: "SCMP_ACT_ALLOW",
+		Syscalls: []*seccompSyscall{
+			{Name: "personality", Action: "SCMP_ACT_ERRNO", Args: []*seccompArg{{Index: 0, Value: 0x0400000, Op: "SCMP_CMP_EQ"}}},
+		},
+	}
+	seccomp, err := p.config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seccomp.Syscalls) != 1 || len(seccomp.Syscalls[0].Args) != 1 || seccomp.Syscalls[0].Args[0].Op != configs.EqualTo {
+		t.Fatalf("Unexpected seccomp config %v", seccomp.Syscalls)
+	}
+}
+
+func TestSeccompProfileInvalid(t *testing.T) {
+	for _, p := range []*seccompProfile{
+		{DefaultAction: "SCMP_ACT_MAYBE"},
+		{DefaultAction: "SCMP_ACT_ALLOW", Syscalls: []*seccompSyscall{{Name: "nonexistent", Action: "SCMP_ACT_KILL"}}},
+		{DefaultAction: "SCMP_ACT_ALLOW", Syscalls: []*seccompSyscall{{Name: "read", Action: "SCMP_ACT_MAYBE"}}},
+		{DefaultAction: "SCMP_ACT_ALLOW", Syscalls: []*seccompSyscall{
+			{Name: "read", Action: "SCMP_ACT_KILL"},
+			{Name: "read", Action: "SCMP_ACT_TRAP"},
+		}},
+		{DefaultAction: "SCMP_ACT_ALLOW", Syscalls: []*seccompSyscall{
+			{Name: "read", Action: "SCMP_ACT_KILL", Args: []*seccompArg{{Index: 0, Op: "SCMP_CMP_NONE"}}},
+		}},
+		{DefaultAction: "SCMP_ACT_ALLOW", Syscalls: []*seccompSyscall{
+			{Name: "read", Action: "SCMP_ACT_KILL", Args: []*seccompArg{{Index: 0, Op: "SCMP_CMP_EQ"}, {Index: 1, Op: "SCMP_CMP_EQ"}}},
+		}},
+		{DefaultAction: "SCMP_ACT_ERRNO", Syscalls: []*seccompSyscall{
+			{Name: "read", Action: "SCMP_ACT_KILL", Args: []*seccompArg{{Index: 0, Op: "SCMP_CMP_EQ"}}},
+		}},
+	} {
+		if _, err := p.config(); err == nil {
+			t.Fatalf("Expected an error for the profile %v", p)
+		}
+	}
+}
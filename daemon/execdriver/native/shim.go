@@ -0,0 +1,461 @@
+// +build linux,cgo
+
+package native
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+// The shim keeps the process of a container started with LiveRestore
+// running when the daemon exits. It is started by the daemon in a session
+// of its own, starts the container and holds the FIFOs of its stdio and the
+// exit status, so that a later daemon can attach to them again.
+const shimName = "native-shim"
+
+// Files of the shim directory of a container.
+const (
+	shimConfigFile  = "config.json"
+	shimProcessFile = "process.json"
+	shimStdin       = "stdin"
+	shimStdout      = "stdout"
+	shimStderr      = "stderr"
+	shimControl     = "control"
+	shimExit        = "exit"
+	shimExitStatus  = "exitStatus"
+	shimLog         = "shim.log"
+)
+
+// shimProcess is the process the shim starts in the container.
+type shimProcess struct {
+	Args  []string `json:"args"`
+	Env   []string `json:"env"`
+	Cwd   string   `json:"cwd"`
+	User  string   `json:"user"`
+	Tty   bool     `json:"tty"`
+	Stdin bool     `json:"stdin"`
+}
+
+func init() {
+	reexec.Register(shimName, shim)
+}
+
+// shimDir returns the directory of the FIFOs and the state of the shim of a
+// container, beside the root of the driver.
+func (d *Driver) shimDir(id string) string {
+	return filepath.Join(filepath.Dir(d.root), "shim", id)
+}
+
+// runShim starts the process of the container with a shim and waits for it
+// to exit.
+func (d *Driver) runShim(c *execdriver.Command, pipes *execdriver.Pipes, startCallback execdriver.StartCallback) (execdriver.ExitStatus, error) {
+	container, err := d.createContainer(c)
+	if err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+
+	p := shimProcess{
+		Args:  append([]string{c.ProcessConfig.Entrypoint}, c.ProcessConfig.Arguments...),
+		Env:   c.ProcessConfig.Env,
+		Cwd:   c.WorkingDir,
+		User:  c.ProcessConfig.User,
+		Tty:   c.ProcessConfig.Tty,
+		Stdin: pipes.Stdin != nil,
+	}
+	dir := d.shimDir(c.ID)
+	if err := createShimDir(dir, container, &p); err != nil {
+		os.RemoveAll(dir)
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	if err := d.startShim(dir); err != nil {
+		d.cleanContainer(c.ID)
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	return d.attachShim(c, pipes, startCallback)
+}
+
+// Reattach implements the exec driver Driver interface,
+// it attaches to the shim of a container started by a previous daemon.
+func (d *Driver) Reattach(c *execdriver.Command, pipes *execdriver.Pipes, reattachCallback execdriver.StartCallback) (execdriver.ExitStatus, error) {
+	if _, err := os.Stat(d.shimDir(c.ID)); err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, fmt.Errorf("Container %s was not started with live restore", c.ID)
+	}
+	return d.attachShim(c, pipes, reattachCallback)
+}
+
+func createShimDir(dir string, container *configs.Config, p *shimProcess) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(dir, shimConfigFile), container); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(dir, shimProcessFile), p); err != nil {
+		return err
+	}
+	for _, name := range []string{shimStdin, shimStdout, shimStderr, shimControl, shimExit} {
+		if err := syscall.Mkfifo(filepath.Join(dir, name), 0600); err != nil {
+			return fmt.Errorf("Creating FIFO %s failed: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// startShim starts the shim and returns once it started the container.
+func (d *Driver) startShim(dir string) error {
+	log, err := os.OpenFile(filepath.Join(dir, shimLog), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer log.Close()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	cmd := &exec.Cmd{
+		Path:       reexec.Self(),
+		Args:       []string{shimName, dir, d.root, d.cgroupDriver},
+		Stdout:     log,
+		Stderr:     log,
+		ExtraFiles: []*os.File{w},
+		// the shim is not part of the session of the daemon, so that it
+		// does not receive the signals sent to it
+		SysProcAttr: &syscall.SysProcAttr{Setsid: true},
+	}
+	err = cmd.Start()
+	w.Close()
+	if err != nil {
+		return err
+	}
+
+	// the shim closes the handshake pipe once the container started, or
+	// writes the error starting it
+	out, err := ioutil.ReadAll(r)
+	if err == nil && len(out) > 0 {
+		err = fmt.Errorf("%s", out)
+	}
+	if err != nil {
+		cmd.Wait()
+		return err
+	}
+	// reap the shim if it exits while this daemon is running
+	go cmd.Wait()
+	return nil
+}
+
+// attachShim attaches the pipes to the FIFOs of a shim and waits for it to
+// exit.
+func (d *Driver) attachShim(c *execdriver.Command, pipes *execdriver.Pipes, startCallback execdriver.StartCallback) (execdriver.ExitStatus, error) {
+	dir := d.shimDir(c.ID)
+	defer d.cleanContainer(c.ID)
+
+	// the shim holds the exit FIFO open until it exits
+	exit, err := openFifo(filepath.Join(dir, shimExit), os.O_RDONLY)
+	if err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	defer exit.Close()
+
+	var copies sync.WaitGroup
+	for name, w := range map[string]io.Writer{shimStdout: pipes.Stdout, shimStderr: pipes.Stderr} {
+		f, err := openFifo(filepath.Join(dir, name), os.O_RDONLY)
+		if err != nil {
+			return execdriver.ExitStatus{ExitCode: -1}, err
+		}
+		copies.Add(1)
+		go func(w io.Writer, f *os.File) {
+			defer copies.Done()
+			defer f.Close()
+			if w == nil {
+				w = ioutil.Discard
+			}
+			io.Copy(w, f)
+		}(w, f)
+	}
+
+	// the FIFOs read by the shim cannot be opened once it exited
+	control, err := openFifo(filepath.Join(dir, shimControl), os.O_WRONLY)
+	if err != nil && !isNoReader(err) {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	term := &shimTerminal{control: control}
+	c.ProcessConfig.Terminal = term
+
+	if pipes.Stdin != nil {
+		stdin, err := openFifo(filepath.Join(dir, shimStdin), os.O_WRONLY)
+		if err != nil && !isNoReader(err) {
+			return execdriver.ExitStatus{ExitCode: -1}, err
+		}
+		if stdin != nil {
+			go func() {
+				io.Copy(stdin, pipes.Stdin)
+				stdin.Close()
+				term.send("closestdin")
+			}()
+		}
+	}
+
+	// the container is gone when it exited while no daemon was attached,
+	// the callback is still called and the exit status is read below
+	pid := 0
+	cont, err := d.factory.Load(c.ID)
+	if err == nil {
+		d.Lock()
+		d.activeContainers[c.ID] = cont
+		d.Unlock()
+		if state, err := cont.State(); err == nil {
+			pid = state.InitProcessPid
+		}
+	}
+	if startCallback != nil {
+		startCallback(&c.ProcessConfig, pid)
+	}
+
+	io.Copy(ioutil.Discard, exit)
+	copies.Wait()
+
+	status, err := readExitStatus(dir)
+	if err != nil {
+		// the shim was killed, make sure the container does not outlive it
+		if cont != nil {
+			killCgroupProcs(cont)
+			cont.Destroy()
+		}
+		return execdriver.ExitStatus{ExitCode: -1}, fmt.Errorf("The shim of container %s exited without the exit status: %v", c.ID, err)
+	}
+	return status, nil
+}
+
+// shimTerminal implements the exec driver Terminal interface for the
+// process of a shim, by sending the requests to its control FIFO.
+type shimTerminal struct {
+	sync.Mutex
+	control *os.File
+}
+
+func (t *shimTerminal) send(format string, args ...interface{}) error {
+	t.Lock()
+	defer t.Unlock()
+	if t.control == nil {
+		return execdriver.ErrNotRunning
+	}
+	_, err := fmt.Fprintf(t.control, format+"\n", args...)
+	return err
+}
+
+// Resize implements Resize method of Terminal interface
+func (t *shimTerminal) Resize(h, w int) error {
+	return t.send("resize %d %d", h, w)
+}
+
+// Close implements Close method of Terminal interface
+func (t *shimTerminal) Close() error {
+	t.Lock()
+	defer t.Unlock()
+	if t.control == nil {
+		return nil
+	}
+	err := t.control.Close()
+	t.control = nil
+	return err
+}
+
+// shimContainer is the container started by a shim.
+type shimContainer struct {
+	cont     libcontainer.Container
+	process  *libcontainer.Process
+	terminal execdriver.Terminal
+	control  *os.File
+	exit     *os.File
+	// stdin is the writer of the stdin FIFO held by the shim until it is
+	// told to close stdin, so that the container only reads the end of its
+	// input once the daemon closed it.
+	stdin *os.File
+}
+
+// shim is the main function of the shim process, its arguments are the shim
+// directory, the root of the driver and the cgroup driver.
+func shim() {
+	if len(os.Args) != 4 {
+		fatal(fmt.Errorf("Usage: %s <dir> <root> <cgroup driver>", shimName))
+	}
+	dir := os.Args[1]
+
+	handshake := os.NewFile(3, "handshake")
+	s, err := startShimContainer(dir, os.Args[2], os.Args[3])
+	if err != nil {
+		fmt.Fprint(handshake, err)
+		os.Exit(1)
+	}
+	handshake.Close()
+
+	go s.controlLoop()
+	status, err := waitForExit(s.cont, s.process)
+	if err != nil {
+		fatal(err)
+	}
+	if err := writeJSON(filepath.Join(dir, shimExitStatus), status); err != nil {
+		fatal(err)
+	}
+	// closing the exit FIFO tells the daemon to read the exit status
+	s.exit.Close()
+	os.Exit(0)
+}
+
+// startShimContainer opens the FIFOs of the shim directory and starts the
+// container. The FIFOs are opened before the daemon is told the container
+// started, so that it can always open them while the shim runs.
+func startShimContainer(dir, root, cgroupDriver string) (*shimContainer, error) {
+	var (
+		container configs.Config
+		process   shimProcess
+		s         = &shimContainer{}
+	)
+	if err := readJSON(filepath.Join(dir, shimConfigFile), &container); err != nil {
+		return nil, err
+	}
+	if err := readJSON(filepath.Join(dir, shimProcessFile), &process); err != nil {
+		return nil, err
+	}
+	factory, err := libcontainer.New(root, cgroupManager(cgroupDriver), libcontainer.InitPath(reexec.Self(), DriverName))
+	if err != nil {
+		return nil, err
+	}
+
+	// The shim holds the other FIFOs open for reading and writing, so that
+	// the container is not stopped by a broken pipe while no daemon is
+	// attached.
+	fifos := make(map[string]*os.File)
+	for _, name := range []string{shimStdout, shimStderr, shimControl, shimExit} {
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_RDWR, 0)
+		if err != nil {
+			return nil, err
+		}
+		fifos[name] = f
+	}
+	s.control, s.exit = fifos[shimControl], fifos[shimExit]
+	pipes := execdriver.NewPipes(nil, fifos[shimStdout], fifos[shimStderr], false)
+
+	if process.Stdin {
+		stdin, err := openFifo(filepath.Join(dir, shimStdin), os.O_RDONLY)
+		if err != nil {
+			return nil, err
+		}
+		if s.stdin, err = os.OpenFile(filepath.Join(dir, shimStdin), os.O_WRONLY, 0); err != nil {
+			return nil, err
+		}
+		pipes.Stdin = stdin
+	}
+
+	s.process = &libcontainer.Process{
+		Args: process.Args,
+		Env:  process.Env,
+		Cwd:  process.Cwd,
+		User: process.User,
+	}
+	processConfig := &execdriver.ProcessConfig{Tty: process.Tty}
+	if err := setupPipes(&container, processConfig, s.process, pipes); err != nil {
+		return nil, err
+	}
+	s.terminal = processConfig.Terminal
+
+	if s.cont, err = factory.Create(filepath.Base(dir), &container); err != nil {
+		return nil, err
+	}
+	if err := s.cont.Start(s.process); err != nil {
+		s.cont.Destroy()
+		return nil, err
+	}
+	return s, nil
+}
+
+// controlLoop handles the requests of the daemon sent to the control FIFO.
+func (s *shimContainer) controlLoop() {
+	scanner := bufio.NewScanner(s.control)
+	for scanner.Scan() {
+		var h, w int
+		if scanner.Text() == "closestdin" {
+			if s.stdin != nil {
+				s.stdin.Close()
+				s.stdin = nil
+			}
+		} else if _, err := fmt.Sscanf(scanner.Text(), "resize %d %d", &h, &w); err == nil {
+			if err := s.terminal.Resize(h, w); err != nil {
+				logrus.Warnf("Resizing the terminal failed: %v", err)
+			}
+		} else {
+			logrus.Warnf("Unknown shim request: %q", scanner.Text())
+		}
+	}
+}
+
+// cgroupManager returns the cgroup manager of the factory for the name of a
+// cgroup driver.
+func cgroupManager(name string) func(*libcontainer.LinuxFactory) error {
+	if name == "systemd" {
+		return libcontainer.SystemdCgroups
+	}
+	return libcontainer.Cgroupfs
+}
+
+// openFifo opens a FIFO without blocking until the other end is opened, an
+// error is returned when it is opened for writing while nothing reads it.
+func openFifo(path string, flag int) (*os.File, error) {
+	f, err := os.OpenFile(path, flag|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.SetNonblock(int(f.Fd()), false); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// isNoReader returns whether an error opening a FIFO for writing is caused
+// by the shim holding its other end having exited.
+func isNoReader(err error) bool {
+	if pathErr, ok := err.(*os.PathError); ok {
+		return pathErr.Err == syscall.ENXIO
+	}
+	return false
+}
+
+func readExitStatus(dir string) (execdriver.ExitStatus, error) {
+	var status execdriver.ExitStatus
+	err := readJSON(filepath.Join(dir, shimExitStatus), &status)
+	return status, err
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
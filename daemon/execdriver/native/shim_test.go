@@ -0,0 +1,87 @@
+// +build linux,cgo
+
+package native
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/daemon/execdriver"
+)
+
+func TestShimTerminal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "native-shim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, shimControl)
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// the control FIFO cannot be opened while no shim reads it
+	if _, err := openFifo(path, os.O_WRONLY); !isNoReader(err) {
+		t.Fatalf("Expected no reader error, got %v", err)
+	}
+	term := &shimTerminal{}
+	if err := term.Resize(24, 80); err != execdriver.ErrNotRunning {
+		t.Fatalf("Expected %v resizing without a shim, got %v", execdriver.ErrNotRunning, err)
+	}
+
+	r, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	control, err := openFifo(path, os.O_WRONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	term = &shimTerminal{control: control}
+	if err := term.Resize(24, 80); err != nil {
+		t.Fatal(err)
+	}
+	if err := term.send("closestdin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := term.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := bufio.NewScanner(r)
+	for _, expected := range []string{"resize 24 80", "closestdin"} {
+		if !scanner.Scan() {
+			t.Fatalf("Expected %q, got %v", expected, scanner.Err())
+		}
+		if scanner.Text() != expected {
+			t.Fatalf("Expected %q, got %q", expected, scanner.Text())
+		}
+	}
+}
+
+func TestShimExitStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "native-shim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := readExitStatus(dir); err == nil {
+		t.Fatal("Expected an error reading a missing exit status")
+	}
+	if err := writeJSON(filepath.Join(dir, shimExitStatus), execdriver.ExitStatus{ExitCode: 137, OOMKilled: true}); err != nil {
+		t.Fatal(err)
+	}
+	status, err := readExitStatus(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ExitCode != 137 || !status.OOMKilled {
+		t.Fatalf("Unexpected exit status %+v", status)
+	}
+}
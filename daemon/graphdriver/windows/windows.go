@@ -13,6 +13,7 @@ import (
 	"github.com/docker/docker/daemon/graphdriver"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/microsoft/hcsshim"
 )
@@ -34,7 +35,7 @@ type WindowsGraphDriver struct {
 }
 
 // New returns a new Windows storage filter driver.
-func InitFilter(home string, options []string) (graphdriver.Driver, error) {
+func InitFilter(home string, options []string, uidMaps, gidMaps []idtools.IDMap) (graphdriver.Driver, error) {
 	logrus.Debugf("WindowsGraphDriver InitFilter at %s", home)
 	d := &WindowsGraphDriver{
 		info: hcsshim.DriverInfo{
@@ -47,7 +48,7 @@ func InitFilter(home string, options []string) (graphdriver.Driver, error) {
 }
 
 // New returns a new Windows differencing disk driver.
-func InitDiff(home string, options []string) (graphdriver.Driver, error) {
+func InitDiff(home string, options []string, uidMaps, gidMaps []idtools.IDMap) (graphdriver.Driver, error) {
 	logrus.Debugf("WindowsGraphDriver InitDiff at %s", home)
 	d := &WindowsGraphDriver{
 		info: hcsshim.DriverInfo{
@@ -324,3 +325,23 @@ func (d *WindowsGraphDriver) Import(id string, layerData archive.ArchiveReader,
 
 	return
 }
+
+// ApplyDiffChain imports the layer id from diff on top of the given parent
+// layers, from the nearest one to the base layer. A base layer is only
+// created, its content is installed with the host.
+func (d *WindowsGraphDriver) ApplyDiffChain(id string, parents []string, diff archive.ArchiveReader) (int64, error) {
+	if len(parents) == 0 {
+		return 0, d.Create(id, "")
+	}
+	return d.Import(id, diff, d.LayerIdsToPaths(parents))
+}
+
+// DiffChain exports the layer id on top of the given parent layers, from
+// the nearest one to the base layer. A base layer cannot be exported and
+// gives an empty archive.
+func (d *WindowsGraphDriver) DiffChain(id string, parents []string) (archive.Archive, error) {
+	if len(parents) == 0 {
+		return archive.Generate()
+	}
+	return d.Export(id, d.LayerIdsToPaths(parents))
+}
@@ -0,0 +1,309 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/runconfig"
+)
+
+const (
+	// Longest healthcheck probe output message to store. Longer messages will be truncated.
+	maxOutputLen = 4096
+
+	// Default interval between probe runs (from the end of the first to the start of the second).
+	// Also the time before the first probe.
+	defaultProbeInterval = 30 * time.Second
+
+	// The maximum length of time a single probe run should take. If the probe takes longer
+	// than this, the check is considered to have failed.
+	defaultProbeTimeout = 30 * time.Second
+
+	// Default number of consecutive failures of the health check
+	// for the container to be considered unhealthy.
+	defaultProbeRetries = 3
+
+	// Maximum number of entries to record
+	maxLogEntries = 5
+)
+
+const (
+	// Exit status codes that can be returned by the probe command.
+
+	exitStatusHealthy = 0 // Container is healthy
+)
+
+// Health holds the current container health-check state
+type Health struct {
+	types.Health
+	stop chan struct{} // Closed to stop the monitor
+}
+
+// String returns a human-readable description of the health-check state
+func (s *Health) String() string {
+	if s.Status == types.Starting {
+		return "health: starting"
+	}
+	return s.Status
+}
+
+// probe implementations know how to run a particular type of probe.
+type probe interface {
+	// Perform one run of the check. Returns the exit code and an optional
+	// short diagnostic string.
+	run(d *Daemon, container *Container) (*types.HealthcheckResult, error)
+}
+
+// cmdProbe implements the "CMD" probe type.
+type cmdProbe struct {
+	// Run the command with the system's default shell instead of execing it directly.
+	shell bool
+}
+
+// exec the healthcheck command in the container.
+// Returns the exit code and probe output (if any)
+func (p *cmdProbe) run(d *Daemon, container *Container) (*types.HealthcheckResult, error) {
+	cmdSlice := container.Config.Healthcheck.Test[1:]
+	if p.shell {
+		if runtime.GOOS != "windows" {
+			cmdSlice = append([]string{"/bin/sh", "-c"}, cmdSlice...)
+		} else {
+			cmdSlice = append([]string{"cmd", "/S /C"}, cmdSlice...)
+		}
+	}
+	entrypoint, args := d.getEntrypointAndArgs(runconfig.NewEntrypoint(), runconfig.NewCommand(cmdSlice...))
+
+	execConfig := &execConfig{
+		ID:         stringid.GenerateNonCryptoID(),
+		OpenStdout: true,
+		OpenStderr: true,
+		ProcessConfig: &execdriver.ProcessConfig{
+			Entrypoint: entrypoint,
+			Arguments:  args,
+			User:       container.Config.User,
+		},
+		Container: container,
+		waitStart: make(chan struct{}),
+	}
+	d.registerExecCommand(execConfig)
+	defer d.unregisterExecCommand(execConfig)
+
+	container.LogEvent("exec_create: " + execConfig.ProcessConfig.Entrypoint + " " + strings.Join(execConfig.ProcessConfig.Arguments, " "))
+
+	output := &limitedBuffer{}
+	if err := d.ContainerExecStart(execConfig.ID, nil, output, output); err != nil {
+		return nil, err
+	}
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: execConfig.ExitCode,
+		Output:   output.String(),
+	}, nil
+}
+
+// Update the container's Status.Health struct based on the latest probe's result.
+func handleProbeResult(c *Container, h *Health, stop chan struct{}, result *types.HealthcheckResult) {
+	c.Lock()
+	defer c.Unlock()
+
+	if h.stop != stop {
+		// The monitor was stopped while the probe was running; the
+		// container is no longer running this check.
+		return
+	}
+
+	retries := c.Config.Healthcheck.Retries
+	if retries <= 0 {
+		retries = defaultProbeRetries
+	}
+
+	oldStatus := h.Status
+
+	if len(h.Log) >= maxLogEntries {
+		h.Log = append(h.Log[len(h.Log)+1-maxLogEntries:], result)
+	} else {
+		h.Log = append(h.Log, result)
+	}
+
+	if result.ExitCode == exitStatusHealthy {
+		h.FailingStreak = 0
+		h.Status = types.Healthy
+	} else {
+		// Failure (including invalid exit code)
+		h.FailingStreak++
+		if h.FailingStreak >= retries {
+			h.Status = types.Unhealthy
+		}
+		// Else we're starting or healthy. Stay in that state.
+	}
+
+	if oldStatus != h.Status {
+		c.LogEvent("health_status: " + h.Status)
+	}
+}
+
+// Run the container's monitoring thread until notified via "stop".
+// There is never more than one monitor thread running per container at a time.
+func monitor(d *Daemon, c *Container, h *Health, stop chan struct{}, probe probe) {
+	probeTimeout := timeoutWithDefault(c.Config.Healthcheck.Timeout, defaultProbeTimeout)
+	probeInterval := timeoutWithDefault(c.Config.Healthcheck.Interval, defaultProbeInterval)
+	for {
+		select {
+		case <-stop:
+			logrus.Debugf("Stop healthcheck monitoring for container %s (received while idle)", c.ID)
+			return
+		case <-time.After(probeInterval):
+			if c.IsPaused() {
+				// Processes in a paused container cannot answer the
+				// probe, so don't count it against the container.
+				continue
+			}
+			logrus.Debugf("Running health check for container %s ...", c.ID)
+			startTime := time.Now()
+			results := make(chan *types.HealthcheckResult, 1)
+			go func() {
+				result, err := probe.run(d, c)
+				if err != nil {
+					logrus.Warnf("Health check for container %s error: %v", c.ID, err)
+					results <- &types.HealthcheckResult{
+						ExitCode: -1,
+						Output:   err.Error(),
+						Start:    startTime,
+						End:      time.Now(),
+					}
+				} else {
+					result.Start = startTime
+					logrus.Debugf("Health check for container %s done (exitCode=%d)", c.ID, result.ExitCode)
+					results <- result
+				}
+			}()
+			select {
+			case <-stop:
+				logrus.Debugf("Stop healthcheck monitoring for container %s (received while probing)", c.ID)
+				return
+			case result := <-results:
+				handleProbeResult(c, h, stop, result)
+			case <-time.After(probeTimeout):
+				logrus.Debugf("Health check for container %s taking too long", c.ID)
+				handleProbeResult(c, h, stop, &types.HealthcheckResult{
+					ExitCode: -1,
+					Output:   fmt.Sprintf("Health check exceeded timeout (%v)", probeTimeout),
+					Start:    startTime,
+					End:      time.Now(),
+				})
+			}
+		}
+	}
+}
+
+// Get a suitable probe implementation for the container's healthcheck configuration.
+// Nil will be returned if no healthcheck was configured or NONE was set.
+func getProbe(c *Container) probe {
+	config := c.Config.Healthcheck
+	if config == nil || len(config.Test) == 0 {
+		return nil
+	}
+	switch config.Test[0] {
+	case "CMD":
+		return &cmdProbe{shell: false}
+	case "CMD-SHELL":
+		return &cmdProbe{shell: true}
+	case "NONE":
+		return nil
+	default:
+		logrus.Warnf("Unknown healthcheck type '%s' (expected 'CMD') in container %s", config.Test[0], c.ID)
+		return nil
+	}
+}
+
+// initHealthMonitor is called from the container monitor when the container
+// has started. It resets the health state and starts the probe loop if the
+// container has a healthcheck configured. The container lock must be held.
+func (daemon *Daemon) initHealthMonitor(c *Container) {
+	probe := getProbe(c)
+	if probe == nil {
+		return
+	}
+
+	// This is needed in case we're auto-restarting
+	daemon.stopHealthchecks(c)
+
+	h := c.State.Health
+	if h == nil {
+		h = &Health{}
+		c.State.Health = h
+	}
+	h.Status = types.Starting
+	h.FailingStreak = 0
+
+	stop := make(chan struct{})
+	h.stop = stop
+	go monitor(daemon, c, h, stop, probe)
+}
+
+// stopHealthchecks is called when the container's process exits. It stops
+// the probe loop, if one is running, leaving the last recorded results in
+// place. The container lock must be held.
+func (daemon *Daemon) stopHealthchecks(c *Container) {
+	h := c.State.Health
+	if h != nil && h.stop != nil {
+		logrus.Debugf("Stopping healthcheck for container %s", c.ID)
+		close(h.stop)
+		h.stop = nil
+	}
+}
+
+// Buffer up to maxOutputLen bytes. Further data is discarded.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	mu        sync.Mutex
+	truncated bool // indicates that data has been lost
+}
+
+// Append to limitedBuffer while there is room.
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bufLen := b.buf.Len()
+	dataLen := len(data)
+	keep := maxOutputLen - bufLen
+	if keep > dataLen {
+		keep = dataLen
+	}
+	if keep > 0 {
+		b.buf.Write(data[:keep])
+	}
+	if keep < dataLen {
+		b.truncated = true
+	}
+	return dataLen, nil
+}
+
+// The contents of the buffer, with "..." appended if it overflowed.
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := b.buf.String()
+	if b.truncated {
+		out = out + "..."
+	}
+	return out
+}
+
+// If configuredValue is zero, use defaultValue instead.
+func timeoutWithDefault(configuredValue time.Duration, defaultValue time.Duration) time.Duration {
+	if configuredValue == 0 {
+		return defaultValue
+	}
+	return configuredValue
+}
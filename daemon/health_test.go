@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/runconfig"
+)
+
+func newHealthTestContainer(retries int) *Container {
+	return &Container{
+		CommonContainer: CommonContainer{
+			ID:    "container_id",
+			State: NewState(),
+			Config: &runconfig.Config{
+				Image: "image_name",
+				Healthcheck: &runconfig.HealthConfig{
+					Test:    []string{"CMD-SHELL", "true"},
+					Retries: retries,
+				},
+			},
+			daemon: &Daemon{EventsService: events.New()},
+		},
+	}
+}
+
+func TestHealthStates(t *testing.T) {
+	c := newHealthTestContainer(2)
+	stop := make(chan struct{})
+	h := &Health{stop: stop}
+	h.Status = types.Starting
+	c.State.Health = h
+
+	handleResult := func(exitCode int) {
+		handleProbeResult(c, h, stop, &types.HealthcheckResult{
+			Start:    time.Now(),
+			End:      time.Now(),
+			ExitCode: exitCode,
+		})
+	}
+
+	// A failure while starting does not make the container unhealthy
+	// until the retries are exhausted.
+	handleResult(1)
+	if h.Status != types.Starting || h.FailingStreak != 1 {
+		t.Fatalf("Expected starting with a failing streak of 1, got %s/%d", h.Status, h.FailingStreak)
+	}
+	handleResult(0)
+	if h.Status != types.Healthy || h.FailingStreak != 0 {
+		t.Fatalf("Expected healthy with no failing streak, got %s/%d", h.Status, h.FailingStreak)
+	}
+	handleResult(1)
+	if h.Status != types.Healthy {
+		t.Fatalf("Expected to stay healthy after one failure, got %s", h.Status)
+	}
+	handleResult(2)
+	if h.Status != types.Unhealthy || h.FailingStreak != 2 {
+		t.Fatalf("Expected unhealthy with a failing streak of 2, got %s/%d", h.Status, h.FailingStreak)
+	}
+
+	for i := 0; i < 2*maxLogEntries; i++ {
+		handleResult(0)
+	}
+	if len(h.Log) != maxLogEntries {
+		t.Fatalf("Expected %d log entries, got %d", maxLogEntries, len(h.Log))
+	}
+
+	// Results from a stopped monitor are dropped.
+	c.daemon.stopHealthchecks(c)
+	handleResult(1)
+	if h.Status != types.Healthy || h.FailingStreak != 0 {
+		t.Fatalf("Expected result of a stopped monitor to be ignored, got %s/%d", h.Status, h.FailingStreak)
+	}
+}
+
+func TestHealthStateString(t *testing.T) {
+	s := NewState()
+	s.SetRunning(1)
+	s.Health = &Health{}
+	s.Health.Status = types.Starting
+	if str := s.String(); !strings.HasSuffix(str, "(health: starting)") {
+		t.Fatalf("Expected the health status in %q", str)
+	}
+	s.Health.Status = types.Unhealthy
+	if str := s.String(); !strings.HasSuffix(str, "(unhealthy)") {
+		t.Fatalf("Expected the health status in %q", str)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{}
+	b.Write([]byte("hello"))
+	if out := b.String(); out != "hello" {
+		t.Fatalf("Expected hello, got %q", out)
+	}
+	b.Write([]byte(strings.Repeat("x", maxOutputLen)))
+	out := b.String()
+	if len(out) != maxOutputLen+len("...") || !strings.HasSuffix(out, "...") {
+		t.Fatalf("Expected the output to be truncated, got %d bytes", len(out))
+	}
+}
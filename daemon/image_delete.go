@@ -104,7 +104,7 @@ func (daemon *Daemon) imgDeleteHelper(name string, list *[]types.ImageDelete, fi
 				*list = append(*list, types.ImageDelete{
 					Untagged: utils.ImageReference(repoName, tag),
 				})
-				daemon.EventsService.Log("untag", img.ID, "")
+				daemon.LogImageEvent(img.ID, utils.ImageReference(repoName, tag), "untag")
 			}
 		}
 	}
@@ -120,7 +120,7 @@ func (daemon *Daemon) imgDeleteHelper(name string, list *[]types.ImageDelete, fi
 			*list = append(*list, types.ImageDelete{
 				Deleted: img.ID,
 			})
-			daemon.EventsService.Log("delete", img.ID, "")
+			daemon.LogImageEvent(img.ID, "", "delete")
 			if img.Parent != "" && !noprune {
 				err := daemon.imgDeleteHelper(img.Parent, list, false, force, noprune)
 				if first {
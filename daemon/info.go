@@ -70,18 +70,18 @@ func (daemon *Daemon) SystemInfo() (*types.Info, error) {
 		NGoroutines:        runtime.NumGoroutine(),
 		SystemTime:         time.Now().Format(time.RFC3339Nano),
 		ExecutionDriver:    daemon.ExecutionDriver().Name(),
-		LoggingDriver:      daemon.defaultLogConfig.Type,
+		LoggingDriver:      daemon.getDefaultLogConfig().Type,
 		NEventsListener:    daemon.EventsService.SubscribersCount(),
 		KernelVersion:      kernelVersion,
 		OperatingSystem:    operatingSystem,
 		IndexServerAddress: registry.IndexServer,
-		RegistryConfig:     daemon.RegistryService.Config,
+		RegistryConfig:     daemon.RegistryService.ServiceConfig(),
 		InitSha1:           dockerversion.INITSHA1,
 		InitPath:           initPath,
 		NCPU:               runtime.NumCPU(),
 		MemTotal:           meminfo.MemTotal,
 		DockerRootDir:      daemon.Config().Root,
-		Labels:             daemon.Config().Labels,
+		Labels:             daemon.getLabels(),
 		ExperimentalBuild:  utils.ExperimentalBuild(),
 	}
 
@@ -95,6 +95,7 @@ func (daemon *Daemon) SystemInfo() (*types.Info, error) {
 		v.OomKillDisable = daemon.SystemConfig().OomKillDisable
 		v.CpuCfsPeriod = daemon.SystemConfig().CPUCfsPeriod
 		v.CpuCfsQuota = daemon.SystemConfig().CPUCfsQuota
+		v.PidsLimit = daemon.SystemConfig().PidsLimit
 	}
 
 	if httpProxy := os.Getenv("http_proxy"); httpProxy != "" {
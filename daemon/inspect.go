@@ -38,20 +38,32 @@ func (daemon *Daemon) getInspectData(container *Container) (*types.ContainerJSON
 	// we need this trick to preserve empty log driver, so
 	// container will use daemon defaults even if daemon change them
 	if hostConfig.LogConfig.Type == "" {
-		hostConfig.LogConfig = daemon.defaultLogConfig
+		hostConfig.LogConfig = daemon.getDefaultLogConfig()
 	}
 
 	containerState := &types.ContainerState{
-		Running:    container.State.Running,
-		Paused:     container.State.Paused,
-		Restarting: container.State.Restarting,
-		OOMKilled:  container.State.OOMKilled,
-		Dead:       container.State.Dead,
-		Pid:        container.State.Pid,
-		ExitCode:   container.State.ExitCode,
-		Error:      container.State.Error,
-		StartedAt:  container.State.StartedAt.Format(time.RFC3339Nano),
-		FinishedAt: container.State.FinishedAt.Format(time.RFC3339Nano),
+		Running:      container.State.Running,
+		Paused:       container.State.Paused,
+		Restarting:   container.State.Restarting,
+		OOMKilled:    container.State.OOMKilled,
+		Dead:         container.State.Dead,
+		Checkpointed: container.State.Checkpointed,
+		Pid:          container.State.Pid,
+		ExitCode:     container.State.ExitCode,
+		Error:        container.State.Error,
+		StartedAt:    container.State.StartedAt.Format(time.RFC3339Nano),
+		FinishedAt:   container.State.FinishedAt.Format(time.RFC3339Nano),
+	}
+	if !container.State.CheckpointedAt.IsZero() {
+		containerState.CheckpointedAt = container.State.CheckpointedAt.Format(time.RFC3339Nano)
+	}
+
+	if h := container.State.Health; h != nil {
+		containerState.Health = &types.Health{
+			Status:        h.Status,
+			FailingStreak: h.FailingStreak,
+			Log:           append([]*types.HealthcheckResult{}, h.Log...),
+		}
 	}
 
 	contJSONBase := &types.ContainerJSONBase{
@@ -7,6 +7,12 @@ import "github.com/docker/docker/api/types"
 // This sets platform-specific fields
 func setPlatformSpecificContainerFields(container *Container, contJSONBase *types.ContainerJSONBase) *types.ContainerJSONBase {
 	contJSONBase.AppArmorProfile = container.AppArmorProfile
+	contJSONBase.SeccompProfile = container.SeccompProfile
+	if container.hostConfig.Privileged {
+		contJSONBase.SeccompProfile = "unconfined"
+	} else if contJSONBase.SeccompProfile == "" {
+		contJSONBase.SeccompProfile = "default"
+	}
 	contJSONBase.ResolvConfPath = container.ResolvConfPath
 	contJSONBase.HostnamePath = container.HostnamePath
 	contJSONBase.HostsPath = container.HostsPath
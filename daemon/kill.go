@@ -12,6 +12,11 @@ func (daemon *Daemon) ContainerKill(name string, sig uint64) error {
 		return err
 	}
 
+	// the monitor does not restart a container the user sent a signal to
+	if container.IsRunning() {
+		container.SetManuallyStopped()
+	}
+
 	// If no signal is passed, or SIGKILL, perform regular Kill (SIGKILL + wait())
 	if sig == 0 || syscall.Signal(sig) == syscall.SIGKILL {
 		if err := container.Kill(); err != nil {
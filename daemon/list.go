@@ -56,7 +56,7 @@ func (daemon *Daemon) Containers(config *ContainersConfig) ([]*types.Container,
 			if !isValidStateString(value) {
 				return nil, errors.New("Unrecognised filter value for status")
 			}
-			if value == "exited" || value == "created" {
+			if value == "exited" || value == "created" || value == "checkpointed" {
 				all = true
 			}
 		}
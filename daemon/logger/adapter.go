@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// LogEntry is the record a log driver plugin receives for each message
+// logged by a container, and sends back when the logs are read. The
+// entries are streamed as a sequence of JSON objects.
+type LogEntry struct {
+	Source   string
+	TimeNano int64
+	Line     []byte
+}
+
+// pluginAdapter sends the messages of a container to a log driver plugin.
+type pluginAdapter struct {
+	driverName string
+	id         string
+	info       Context
+	plugin     logPlugin
+	fifoPath   string
+
+	mu     sync.Mutex // protects stream and enc
+	stream io.WriteCloser
+	enc    *json.Encoder
+}
+
+func (a *pluginAdapter) Log(msg *Message) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.enc.Encode(&LogEntry{
+		Source:   msg.Source,
+		TimeNano: msg.Timestamp.UnixNano(),
+		Line:     msg.Line,
+	})
+}
+
+func (a *pluginAdapter) Name() string {
+	return a.driverName
+}
+
+// Close closes the FIFO, so the plugin reads everything that was logged
+// before it is told to stop logging.
+func (a *pluginAdapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.stream.Close(); err != nil {
+		logrus.Errorf("Error closing logger stream for %s: %v", a.id, err)
+	}
+	err := a.plugin.StopLogging(a.fifoPath)
+	if rmErr := os.Remove(a.fifoPath); rmErr != nil && !os.IsNotExist(rmErr) {
+		logrus.Errorf("Error removing logger FIFO %s: %v", a.fifoPath, rmErr)
+	}
+	return err
+}
+
+// pluginAdapterWithRead is the adapter for the plugins which can also
+// read back the messages of a container.
+type pluginAdapterWithRead struct {
+	*pluginAdapter
+}
+
+func (a *pluginAdapterWithRead) ReadLogs(config ReadConfig) *LogWatcher {
+	watcher := NewLogWatcher()
+
+	go func() {
+		defer close(watcher.Msg)
+
+		stream, err := a.plugin.ReadLogs(a.info, config)
+		if err != nil {
+			watcher.Err <- err
+			return
+		}
+		defer stream.Close()
+
+		// Unblock the decoder when the watcher is closed.
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-watcher.WatchClose():
+				stream.Close()
+			case <-done:
+			}
+		}()
+
+		dec := json.NewDecoder(stream)
+		for {
+			var e LogEntry
+			if err := dec.Decode(&e); err != nil {
+				if err != io.EOF {
+					select {
+					case <-watcher.WatchClose():
+					default:
+						watcher.Err <- err
+					}
+				}
+				return
+			}
+
+			// Readers expect newline terminated lines, like the ones
+			// read from the json-file driver.
+			msg := &Message{
+				ContainerID: a.id,
+				Source:      e.Source,
+				Line:        append(e.Line, '\n'),
+				Timestamp:   time.Unix(0, e.TimeNano),
+			}
+			if !config.Since.IsZero() && msg.Timestamp.Before(config.Since) {
+				continue
+			}
+
+			select {
+			case watcher.Msg <- msg:
+			case <-watcher.WatchClose():
+				return
+			}
+		}
+	}()
+
+	return watcher
+}
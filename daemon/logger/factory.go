@@ -6,6 +6,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/Sirupsen/logrus"
 )
 
 // Creator builds a logging driver instance with given context.
@@ -25,6 +27,7 @@ type Context struct {
 	ContainerImageID    string
 	ContainerImageName  string
 	ContainerCreated    time.Time
+	ContainerLabels     map[string]string
 	LogPath             string
 }
 
@@ -79,11 +82,17 @@ func (lf *logdriverFactory) registerLogOptValidator(name string, l LogOptValidat
 
 func (lf *logdriverFactory) get(name string) (Creator, error) {
 	lf.m.Lock()
-	defer lf.m.Unlock()
-
 	c, ok := lf.registry[name]
-	if !ok {
-		return c, fmt.Errorf("logger: no log driver named '%s' is registered", name)
+	lf.m.Unlock()
+	if ok {
+		return c, nil
+	}
+
+	// Not a built-in driver, look for a LogDriver plugin
+	c, err := getPlugin(name)
+	if err != nil {
+		logrus.Debugf("%v", err)
+		return nil, fmt.Errorf("logger: no log driver named '%s' is registered", name)
 	}
 	return c, nil
 }
@@ -111,6 +120,8 @@ func RegisterLogOptValidator(name string, l LogOptValidator) error {
 }
 
 // GetLogDriver provides the logging driver builder for a logging driver name.
+// If no built-in driver has this name, a LogDriver plugin with the name is
+// looked up.
 func GetLogDriver(name string) (Creator, error) {
 	return factory.get(name)
 }
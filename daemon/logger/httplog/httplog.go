@@ -0,0 +1,449 @@
+// Package httplog provides the log driver for posting container logs as
+// batches of JSON messages to an HTTP endpoint, such as an HTTP event
+// collector.
+package httplog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/pkg/tlsconfig"
+)
+
+const (
+	name = "httplog"
+
+	urlKey                = "httplog-url"
+	tokenKey              = "httplog-token"
+	tokenHeaderKey        = "httplog-token-header"
+	tagKey                = "httplog-tag"
+	batchSizeKey          = "httplog-batch-size"
+	batchIntervalKey      = "httplog-batch-interval"
+	bufferSizeKey         = "httplog-buffer-size"
+	gzipKey               = "httplog-gzip"
+	caCertKey             = "httplog-tls-ca-cert"
+	certKey               = "httplog-tls-cert"
+	keyKey                = "httplog-tls-key"
+	insecureSkipVerifyKey = "httplog-tls-insecure-skip-verify"
+
+	defaultTokenHeader   = "Authorization"
+	defaultBatchSize     = 100
+	defaultBatchInterval = 5 * time.Second
+	defaultTag           = "{{.ID}}"
+)
+
+// message is the JSON object posted for every log message.
+type message struct {
+	Time          string `json:"time"`
+	Tag           string `json:"tag"`
+	Source        string `json:"source"`
+	Line          string `json:"line"`
+	ContainerID   string `json:"container_id"`
+	ContainerName string `json:"container_name"`
+}
+
+// receiver is the data the tag template is executed with.
+type receiver struct {
+	ID     string
+	FullID string
+	Name   string
+	Labels map[string]string
+}
+
+type httpLogger struct {
+	client      *http.Client
+	url         string
+	token       string
+	tokenHeader string
+	gzip        bool
+
+	tag           string
+	containerID   string
+	containerName string
+
+	batchSize     int
+	batchInterval time.Duration
+	bufferSize    int
+
+	mu       sync.Mutex // protects closed
+	closed   bool
+	messages chan *message
+	done     chan struct{}
+}
+
+func init() {
+	if err := logger.RegisterLogDriver(name, New); err != nil {
+		logrus.Fatal(err)
+	}
+	if err := logger.RegisterLogOptValidator(name, ValidateLogOpt); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// New creates an httplog logger using the configuration passed in on the
+// context. The messages are posted as a JSON array every time
+// httplog-batch-size messages are logged, and at least every
+// httplog-batch-interval. Messages which could not be posted are kept and
+// retried with the next batch, up to httplog-buffer-size messages.
+func New(ctx logger.Context) (logger.Logger, error) {
+	cfg := ctx.Config
+
+	u, err := parseURL(cfg[urlKey])
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := parseTag(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize, err := parseInt(cfg, batchSizeKey, defaultBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	bufferSize, err := parseInt(cfg, bufferSizeKey, 10*batchSize)
+	if err != nil {
+		return nil, err
+	}
+	if bufferSize < batchSize {
+		return nil, fmt.Errorf("%s cannot be less than %s", bufferSizeKey, batchSizeKey)
+	}
+	batchInterval := defaultBatchInterval
+	if v, ok := cfg[batchIntervalKey]; ok {
+		if batchInterval, err = time.ParseDuration(v); err != nil {
+			return nil, err
+		}
+		if batchInterval <= 0 {
+			return nil, fmt.Errorf("%s must be a positive duration", batchIntervalKey)
+		}
+	}
+	var gz bool
+	if v, ok := cfg[gzipKey]; ok {
+		if gz, err = strconv.ParseBool(v); err != nil {
+			return nil, err
+		}
+	}
+
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if u.Scheme == "https" {
+		tlsConfig, err := parseTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	tokenHeader := defaultTokenHeader
+	if v := cfg[tokenHeaderKey]; v != "" {
+		tokenHeader = v
+	}
+
+	l := &httpLogger{
+		client:        &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		url:           u.String(),
+		token:         cfg[tokenKey],
+		tokenHeader:   tokenHeader,
+		gzip:          gz,
+		tag:           tag,
+		containerID:   ctx.ContainerID,
+		containerName: ctx.ContainerName,
+		batchSize:     batchSize,
+		batchInterval: batchInterval,
+		bufferSize:    bufferSize,
+		messages:      make(chan *message, batchSize),
+		done:          make(chan struct{}),
+	}
+	logrus.Debugf("logging driver httplog configured for container:%s, url:%s, tag:%s.", ctx.ContainerID, l.url, tag)
+
+	go l.worker()
+	return l, nil
+}
+
+func (l *httpLogger) Log(msg *logger.Message) error {
+	m := &message{
+		Time:          msg.Timestamp.UTC().Format(time.RFC3339Nano),
+		Tag:           l.tag,
+		Source:        msg.Source,
+		Line:          string(msg.Line),
+		ContainerID:   l.containerID,
+		ContainerName: l.containerName,
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return fmt.Errorf("httplog: logger is closed")
+	}
+	l.messages <- m
+	return nil
+}
+
+// worker batches the messages and posts them until the logger is closed.
+func (l *httpLogger) worker() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.batchInterval)
+	defer ticker.Stop()
+
+	var (
+		pending []*message
+		err     error
+	)
+	for {
+		select {
+		case m, ok := <-l.messages:
+			if !ok {
+				// last attempt to send what is left
+				if pending, err = l.flush(pending); err != nil {
+					logrus.Errorf("httplog: dropping %d messages of container %s: %v", len(pending), l.containerID, err)
+				}
+				return
+			}
+			pending = append(pending, m)
+			// once posting failed, only retry on the next tick
+			if err == nil && len(pending) >= l.batchSize {
+				pending, err = l.flush(pending)
+			}
+			pending = l.trim(pending)
+		case <-ticker.C:
+			pending, err = l.flush(pending)
+			pending = l.trim(pending)
+		}
+	}
+}
+
+// flush posts the pending messages, batchSize messages at a time, and
+// returns the messages which could not be posted.
+func (l *httpLogger) flush(pending []*message) ([]*message, error) {
+	for len(pending) > 0 {
+		n := len(pending)
+		if n > l.batchSize {
+			n = l.batchSize
+		}
+		if err := l.post(pending[:n]); err != nil {
+			logrus.Debugf("httplog: error posting messages of container %s, will retry: %v", l.containerID, err)
+			return pending, err
+		}
+		pending = pending[n:]
+	}
+	return nil, nil
+}
+
+// trim drops the oldest pending messages when there are more than
+// bufferSize of them.
+func (l *httpLogger) trim(pending []*message) []*message {
+	if len(pending) <= l.bufferSize {
+		return pending
+	}
+	dropped := len(pending) - l.bufferSize
+	logrus.Errorf("httplog: buffer full, dropping %d messages of container %s", dropped, l.containerID)
+	return pending[dropped:]
+}
+
+// post sends a batch of messages to the endpoint.
+func (l *httpLogger) post(batch []*message) error {
+	var body bytes.Buffer
+	var w io.Writer = &body
+	var gzw *gzip.Writer
+	if l.gzip {
+		gzw = gzip.NewWriter(&body)
+		w = gzw
+	}
+	if err := json.NewEncoder(w).Encode(batch); err != nil {
+		return err
+	}
+	if gzw != nil {
+		if err := gzw.Close(); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest("POST", l.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if l.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if l.token != "" {
+		req.Header.Set(l.tokenHeader, l.token)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(b))
+	}
+	return nil
+}
+
+// Close sends the messages left and stops the logger.
+func (l *httpLogger) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.messages)
+	l.mu.Unlock()
+
+	<-l.done
+	return nil
+}
+
+func (l *httpLogger) Name() string {
+	return name
+}
+
+// ValidateLogOpt looks for httplog specific log options.
+func ValidateLogOpt(cfg map[string]string) error {
+	for key := range cfg {
+		switch key {
+		case urlKey:
+		case tokenKey:
+		case tokenHeaderKey:
+		case tagKey:
+		case batchSizeKey:
+		case batchIntervalKey:
+		case bufferSizeKey:
+		case gzipKey:
+		case caCertKey:
+		case certKey:
+		case keyKey:
+		case insecureSkipVerifyKey:
+		default:
+			return fmt.Errorf("unknown log opt '%s' for httplog log driver", key)
+		}
+	}
+	if _, err := parseURL(cfg[urlKey]); err != nil {
+		return err
+	}
+	if _, err := template.New("tag").Parse(cfg[tagKey]); err != nil {
+		return err
+	}
+	for _, key := range []string{batchSizeKey, bufferSizeKey} {
+		if _, err := parseInt(cfg, key, 1); err != nil {
+			return err
+		}
+	}
+	if v, ok := cfg[batchIntervalKey]; ok {
+		if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+			return fmt.Errorf("httplog: invalid value for %s: %s", batchIntervalKey, v)
+		}
+	}
+	for _, key := range []string{gzipKey, insecureSkipVerifyKey} {
+		if v, ok := cfg[key]; ok {
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("httplog: invalid value for %s: %s", key, v)
+			}
+		}
+	}
+	return nil
+}
+
+func parseURL(s string) (*url.URL, error) {
+	if s == "" {
+		return nil, fmt.Errorf("httplog: please provide %s as http(s)://host[:port]/path", urlKey)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("httplog: please provide %s as http(s)://host[:port]/path", urlKey)
+	}
+	return u, nil
+}
+
+func parseTag(ctx logger.Context) (string, error) {
+	text := defaultTag
+	if v := ctx.Config[tagKey]; v != "" {
+		text = v
+	}
+	tmpl, err := template.New("tag").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	id := ctx.ContainerID
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	r := &receiver{
+		ID:     id,
+		FullID: ctx.ContainerID,
+		Name:   ctx.ContainerName,
+		Labels: ctx.ContainerLabels,
+	}
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, r); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func parseInt(cfg map[string]string, key string, defaultValue int) (int, error) {
+	v, ok := cfg[key]
+	if !ok {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("httplog: invalid value for %s: %s", key, v)
+	}
+	return n, nil
+}
+
+// parseTLSConfig returns the TLS configuration for the connections to the
+// endpoint. The system's trusted certificates are used unless a CA
+// certificate is given.
+func parseTLSConfig(cfg map[string]string) (*tls.Config, error) {
+	opts := tlsconfig.Options{
+		CAFile:   cfg[caCertKey],
+		CertFile: cfg[certKey],
+		KeyFile:  cfg[keyKey],
+	}
+	if v, ok := cfg[insecureSkipVerifyKey]; ok {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, err
+		}
+		opts.InsecureSkipVerify = insecure
+	}
+	if opts.CAFile != "" || opts.InsecureSkipVerify {
+		return tlsconfig.Client(opts)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   tlsconfig.ClientDefault.MinVersion,
+		CipherSuites: tlsconfig.ClientDefault.CipherSuites,
+	}
+	if opts.CertFile != "" && opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Could not load X509 key pair: %v. Make sure the key is not encrypted", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
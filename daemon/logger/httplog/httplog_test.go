@@ -0,0 +1,232 @@
+package httplog
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+)
+
+const testContainerID = "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+
+// testServer records the batches of messages posted to it.
+type testServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	batches  [][]message
+	headers  []http.Header
+	failures int // number of requests to fail before accepting batches
+	received chan struct{}
+}
+
+func newTestServer(t *testing.T, tls bool) *testServer {
+	s := &testServer{received: make(chan struct{}, 100)}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.failures > 0 {
+			s.failures--
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			body = gz
+		}
+		var batch []message
+		if err := json.NewDecoder(body).Decode(&batch); err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.batches = append(s.batches, batch)
+		s.headers = append(s.headers, r.Header)
+		s.received <- struct{}{}
+	})
+	if tls {
+		s.Server = httptest.NewTLSServer(handler)
+	} else {
+		s.Server = httptest.NewServer(handler)
+	}
+	return s
+}
+
+func (s *testServer) waitBatches(t *testing.T, n int) [][]message {
+	for i := 0; i < n; i++ {
+		select {
+		case <-s.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timeout waiting for batch %d", i+1)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+func newTestLogger(t *testing.T, config map[string]string) logger.Logger {
+	l, err := New(logger.Context{
+		ContainerID:     testContainerID,
+		ContainerName:   "/test",
+		ContainerLabels: map[string]string{"app": "web"},
+		Config:          config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func logLines(t *testing.T, l logger.Logger, lines ...string) {
+	for _, line := range lines {
+		if err := l.Log(&logger.Message{ContainerID: testContainerID, Line: []byte(line), Source: "stdout", Timestamp: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHTTPLogBatchSize(t *testing.T) {
+	s := newTestServer(t, false)
+	defer s.Close()
+
+	l := newTestLogger(t, map[string]string{
+		urlKey:           s.URL + "/collector",
+		tokenKey:         "Splunk secret",
+		tagKey:           `{{.Name}}/{{index .Labels "app"}}/{{.ID}}`,
+		batchSizeKey:     "2",
+		batchIntervalKey: "1h",
+	})
+	defer l.Close()
+
+	logLines(t, l, "line1", "line2", "line3", "line4")
+	batches := s.waitBatches(t, 2)
+	if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 2 {
+		t.Fatalf("Expected 2 batches of 2 messages, got %v", batches)
+	}
+	m := batches[0][0]
+	if m.Line != "line1" || m.Source != "stdout" || m.ContainerID != testContainerID || m.ContainerName != "/test" {
+		t.Fatalf("Unexpected message %+v", m)
+	}
+	if expected := "/test/web/" + testContainerID[:12]; m.Tag != expected {
+		t.Fatalf("Expected tag %s, got %s", expected, m.Tag)
+	}
+	if batches[1][1].Line != "line4" {
+		t.Fatalf("Expected line4 last, got %s", batches[1][1].Line)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token := s.headers[0].Get("Authorization"); token != "Splunk secret" {
+		t.Fatalf("Expected the token header to be set, got %q", token)
+	}
+}
+
+func TestHTTPLogIntervalGzip(t *testing.T) {
+	s := newTestServer(t, false)
+	defer s.Close()
+
+	l := newTestLogger(t, map[string]string{
+		urlKey:           s.URL,
+		batchIntervalKey: "50ms",
+		gzipKey:          "true",
+	})
+	defer l.Close()
+
+	logLines(t, l, "line1")
+	batches := s.waitBatches(t, 1)
+	if len(batches[0]) != 1 || batches[0][0].Line != "line1" {
+		t.Fatalf("Expected line1 to be sent after the interval, got %v", batches)
+	}
+	if tag := batches[0][0].Tag; tag != testContainerID[:12] {
+		t.Fatalf("Expected the default tag, got %s", tag)
+	}
+}
+
+func TestHTTPLogRetry(t *testing.T) {
+	s := newTestServer(t, false)
+	defer s.Close()
+	s.failures = 2
+
+	l := newTestLogger(t, map[string]string{
+		urlKey:           s.URL,
+		batchSizeKey:     "2",
+		bufferSizeKey:    "4",
+		batchIntervalKey: "50ms",
+	})
+	defer l.Close()
+
+	// the oldest messages are dropped while the endpoint fails
+	logLines(t, l, "line1", "line2", "line3", "line4", "line5", "line6")
+	batches := s.waitBatches(t, 2)
+	var lines []string
+	for _, b := range batches {
+		for _, m := range b {
+			lines = append(lines, m.Line)
+		}
+	}
+	if len(lines) != 4 || lines[0] != "line3" || lines[3] != "line6" {
+		t.Fatalf("Expected line3 to line6 to be retried, got %v", lines)
+	}
+}
+
+func TestHTTPLogCloseFlushes(t *testing.T) {
+	s := newTestServer(t, true)
+	defer s.Close()
+
+	l := newTestLogger(t, map[string]string{
+		urlKey:                s.URL,
+		batchIntervalKey:      "1h",
+		insecureSkipVerifyKey: "true",
+	})
+	logLines(t, l, "line1", "line2")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	batches := s.waitBatches(t, 1)
+	if len(batches[0]) != 2 {
+		t.Fatalf("Expected the messages to be sent on close, got %v", batches)
+	}
+	if err := l.Log(&logger.Message{Line: []byte("late")}); err == nil {
+		t.Fatal("Expected an error logging to a closed logger")
+	}
+}
+
+func TestValidateLogOpt(t *testing.T) {
+	valid := map[string]string{
+		urlKey:           "https://collector.example.com/services/collector",
+		tagKey:           "{{.Name}}",
+		batchSizeKey:     "10",
+		batchIntervalKey: "2s",
+		gzipKey:          "true",
+	}
+	if err := ValidateLogOpt(valid); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cfg := range []map[string]string{
+		{},
+		{urlKey: "udp://collector.example.com"},
+		{urlKey: "http://collector.example.com", "unknown": "value"},
+		{urlKey: "http://collector.example.com", tagKey: "{{.Name"},
+		{urlKey: "http://collector.example.com", batchSizeKey: "0"},
+		{urlKey: "http://collector.example.com", batchIntervalKey: "often"},
+		{urlKey: "http://collector.example.com", gzipKey: "maybe"},
+	} {
+		if err := ValidateLogOpt(cfg); err == nil {
+			t.Fatalf("Expected an error for %v", cfg)
+		}
+	}
+}
@@ -5,9 +5,11 @@ package jsonfilelog
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"sync"
@@ -29,15 +31,18 @@ const (
 	// Name is the name of the file that the jsonlogger logs to.
 	Name               = "json-file"
 	maxJSONDecodeRetry = 10
+	compressedExt      = ".gz"
 )
 
 // JSONFileLogger is Logger implementation for default Docker logging.
 type JSONFileLogger struct {
 	buf          *bytes.Buffer
-	f            *os.File   // store for closing
-	mu           sync.Mutex // protects buffer
-	capacity     int64      //maximum size of each file
-	n            int        //maximum number of files
+	f            *os.File       // store for closing
+	mu           sync.Mutex     // protects buffer
+	capacity     int64          //maximum size of each file
+	n            int            //maximum number of files
+	compress     bool           // whether rotated files are compressed
+	compressing  sync.WaitGroup // tracks the compression of the last rotated file
 	ctx          logger.Context
 	readers      map[*logger.LogWatcher]struct{} // stores the active log followers
 	notifyRotate *pubsub.Publisher
@@ -77,12 +82,23 @@ func New(ctx logger.Context) (logger.Logger, error) {
 			return nil, fmt.Errorf("max-files cannot be less than 1")
 		}
 	}
+	var compress bool
+	if compressString, ok := ctx.Config["compress"]; ok {
+		compress, err = strconv.ParseBool(compressString)
+		if err != nil {
+			return nil, err
+		}
+		if compress && maxFiles < 2 {
+			return nil, fmt.Errorf("compress cannot be true when max-file is less than 2")
+		}
+	}
 	return &JSONFileLogger{
 		f:            log,
 		buf:          bytes.NewBuffer(nil),
 		ctx:          ctx,
 		capacity:     capval,
 		n:            maxFiles,
+		compress:     compress,
 		readers:      make(map[*logger.LogWatcher]struct{}),
 		notifyRotate: pubsub.NewPublisher(0, 1),
 	}, nil
@@ -119,7 +135,9 @@ func writeLog(l *JSONFileLogger) (int64, error) {
 		if err := l.f.Close(); err != nil {
 			return -1, err
 		}
-		if err := rotate(name, l.n); err != nil {
+		// the previous rotated file must be compressed before it is shifted
+		l.compressing.Wait()
+		if err := rotate(name, l.n, l.compress); err != nil {
 			return -1, err
 		}
 		file, err := os.OpenFile(name, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0666)
@@ -128,6 +146,16 @@ func writeLog(l *JSONFileLogger) (int64, error) {
 		}
 		l.f = file
 		l.notifyRotate.Publish(struct{}{})
+
+		if l.compress && l.n > 1 {
+			l.compressing.Add(1)
+			go func() {
+				defer l.compressing.Done()
+				if err := compressFile(name + ".1"); err != nil {
+					logrus.Errorf("Error compressing log file %s.1: %v", name, err)
+				}
+			}()
+		}
 	}
 	return writeToBuf(l)
 }
@@ -140,13 +168,20 @@ func writeToBuf(l *JSONFileLogger) (int64, error) {
 	return i, err
 }
 
-func rotate(name string, n int) error {
+// rotate shifts the rotated files by one and renames the current file to
+// name.1. With compress, all the rotated files but name.1, which is yet to
+// be compressed, have the compressedExt extension.
+func rotate(name string, n int, compress bool) error {
 	if n < 2 {
 		return nil
 	}
+	var ext string
+	if compress {
+		ext = compressedExt
+	}
 	for i := n - 1; i > 1; i-- {
-		oldFile := name + "." + strconv.Itoa(i)
-		replacingFile := name + "." + strconv.Itoa(i-1)
+		oldFile := name + "." + strconv.Itoa(i) + ext
+		replacingFile := name + "." + strconv.Itoa(i-1) + ext
 		if err := backup(oldFile, replacingFile); err != nil {
 			return err
 		}
@@ -157,6 +192,40 @@ func rotate(name string, n int) error {
 	return nil
 }
 
+// compressFile gzips the file at name to name.gz and removes it. The
+// compressed file is written under a temporary name first, so that readers
+// never see it partially written.
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := name + compressedExt + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	w := gzip.NewWriter(dst)
+	_, err = io.Copy(w, src)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, name+compressedExt); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(name)
+}
+
 // backup renames a file from curr to old, creating an empty file curr if it does not exist.
 func backup(old, curr string) error {
 	if _, err := os.Stat(old); !os.IsNotExist(err) {
@@ -181,10 +250,24 @@ func ValidateLogOpt(cfg map[string]string) error {
 		switch key {
 		case "max-file":
 		case "max-size":
+		case "compress":
 		default:
 			return fmt.Errorf("unknown log opt '%s' for json-file log driver", key)
 		}
 	}
+	if compressString, ok := cfg["compress"]; ok {
+		compress, err := strconv.ParseBool(compressString)
+		if err != nil {
+			return fmt.Errorf("invalid value for log opt 'compress': %s", compressString)
+		}
+		maxFiles := 1
+		if maxFileString, ok := cfg["max-file"]; ok {
+			maxFiles, _ = strconv.Atoi(maxFileString)
+		}
+		if compress && maxFiles < 2 {
+			return fmt.Errorf("compress cannot be true when max-file is less than 2")
+		}
+	}
 	return nil
 }
 
@@ -202,6 +285,7 @@ func (l *JSONFileLogger) Close() error {
 		delete(l.readers, r)
 	}
 	l.mu.Unlock()
+	l.compressing.Wait()
 	return err
 }
 
@@ -238,7 +322,7 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	pth := l.ctx.LogPath
 	var files []io.ReadSeeker
 	for i := l.n; i > 1; i-- {
-		f, err := os.Open(fmt.Sprintf("%s.%d", pth, i-1))
+		f, err := openRotatedFile(fmt.Sprintf("%s.%d", pth, i-1))
 		if err != nil {
 			if !os.IsNotExist(err) {
 				logWatcher.Err <- err
@@ -286,6 +370,82 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	l.notifyRotate.Evict(notifyRotate)
 }
 
+type readSeekCloser interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// openRotatedFile opens the rotated file at name, or its compressed version
+// if there is one. Compressed files are decompressed to a temporary file
+// which is removed once closed, since tailing needs to seek.
+func openRotatedFile(name string) (readSeekCloser, error) {
+	f, err := os.Open(name + compressedExt)
+	if os.IsNotExist(err) {
+		f, err = os.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if os.IsNotExist(err) {
+			// the file may have been compressed in the meantime
+			f, err = os.Open(name + compressedExt)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	df, err := decompressFile(f)
+	if err != nil {
+		return nil, err
+	}
+	return df, nil
+}
+
+// decompressedFile is a temporary file holding the content of a compressed
+// log file. It is removed when closed.
+type decompressedFile struct {
+	*os.File
+}
+
+func (f *decompressedFile) Close() error {
+	err := f.File.Close()
+	os.Remove(f.Name())
+	return err
+}
+
+func decompressFile(f *os.File) (*decompressedFile, error) {
+	tmp, err := ioutil.TempFile("", "docker-log-")
+	if err != nil {
+		return nil, err
+	}
+	df := &decompressedFile{tmp}
+
+	fi, err := f.Stat()
+	if err != nil {
+		df.Close()
+		return nil, err
+	}
+	// rotation creates empty files in place of the missing ones
+	if fi.Size() > 0 {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			df.Close()
+			return nil, err
+		}
+		_, err = io.Copy(tmp, gz)
+		gz.Close()
+		if err != nil {
+			df.Close()
+			return nil, err
+		}
+	}
+	if _, err := tmp.Seek(0, os.SEEK_SET); err != nil {
+		df.Close()
+		return nil, err
+	}
+	return df, nil
+}
+
 func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since time.Time) {
 	var rdr io.Reader = f
 	if tail > 0 {
@@ -322,6 +482,7 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 		return
 	}
 	defer fileWatcher.Close()
+	defer func() { f.Close() }()
 	if err := fileWatcher.Add(f.Name()); err != nil {
 		logWatcher.Err <- err
 		return
@@ -352,7 +513,24 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 			case <-logWatcher.WatchClose():
 				return
 			case <-notifyRotate:
+				// send what was written to the file before it was rotated
+				dec = json.NewDecoder(f)
+				for {
+					msg, err := decodeLogLine(dec, l)
+					if err != nil {
+						break
+					}
+					if !since.IsZero() && msg.Timestamp.Before(since) {
+						continue
+					}
+					select {
+					case logWatcher.Msg <- msg:
+					case <-logWatcher.WatchClose():
+						return
+					}
+				}
 				fileWatcher.Remove(f.Name())
+				f.Close()
 
 				f, err = os.Open(f.Name())
 				if err != nil {
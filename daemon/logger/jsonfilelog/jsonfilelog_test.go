@@ -149,3 +149,149 @@ func TestJSONFileLoggerWithOpts(t *testing.T) {
 	}
 
 }
+
+func readLines(t *testing.T, watcher *logger.LogWatcher, n int) []string {
+	var lines []string
+	for n < 0 || len(lines) < n {
+		select {
+		case msg, ok := <-watcher.Msg:
+			if !ok {
+				if n >= 0 {
+					t.Fatalf("Expected %d lines, got %q", n, lines)
+				}
+				return lines
+			}
+			lines = append(lines, string(msg.Line))
+		case err := <-watcher.Err:
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timeout reading logs, got %q", lines)
+		}
+	}
+	return lines
+}
+
+func TestJSONFileLoggerCompress(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	config := map[string]string{"max-file": "3", "max-size": "1k", "compress": "true"}
+	l, err := New(logger.Context{
+		ContainerID: cid,
+		LogPath:     filename,
+		Config:      config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	base := time.Unix(1445000000, 0).UTC()
+	for i := 0; i < 60; i++ {
+		if err := l.Log(&logger.Message{ContainerID: cid, Line: []byte("line" + strconv.Itoa(i)), Source: "src1", Timestamp: base.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.(*JSONFileLogger).compressing.Wait()
+
+	for _, name := range []string{filename + ".1.gz", filename + ".2.gz"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Fatalf("Expected compressed file %s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filename + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("Expected the uncompressed rotated file to be removed, got %v", err)
+	}
+
+	r := l.(logger.LogReader)
+	lines := readLines(t, r.ReadLogs(logger.ReadConfig{Tail: -1}), -1)
+	if len(lines) == 0 || len(lines) >= 60 {
+		t.Fatalf("Expected the oldest lines to be dropped, got %q", lines)
+	}
+	first := 60 - len(lines)
+	for i, line := range lines {
+		if expected := "line" + strconv.Itoa(first+i) + "\n"; line != expected {
+			t.Fatalf("Expected %q at position %d, got %q", expected, i, line)
+		}
+	}
+
+	// the last 20 lines span the current file and a compressed one
+	lines = readLines(t, r.ReadLogs(logger.ReadConfig{Tail: 20}), -1)
+	if len(lines) != 20 || lines[0] != "line40\n" || lines[19] != "line59\n" {
+		t.Fatalf("Expected line40 to line59, got %q", lines)
+	}
+
+	lines = readLines(t, r.ReadLogs(logger.ReadConfig{Tail: -1, Since: base.Add(30 * time.Second)}), -1)
+	if len(lines) != 30 || lines[0] != "line30\n" {
+		t.Fatalf("Expected line30 to line59, got %q", lines)
+	}
+}
+
+func TestJSONFileLoggerFollowRotate(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	config := map[string]string{"max-file": "3", "max-size": "1k", "compress": "true"}
+	l, err := New(logger.Context{
+		ContainerID: cid,
+		LogPath:     filename,
+		Config:      config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Log(&logger.Message{ContainerID: cid, Line: []byte("line0"), Source: "src1"}); err != nil {
+		t.Fatal(err)
+	}
+	watcher := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1, Follow: true})
+	defer watcher.Close()
+	if lines := readLines(t, watcher, 1); lines[0] != "line0\n" {
+		t.Fatalf("Expected line0, got %q", lines)
+	}
+	// let the reader start following the file
+	time.Sleep(100 * time.Millisecond)
+
+	// every batch of lines triggers at most one rotation
+	n := 1
+	for batch := 0; batch < 10; batch++ {
+		for i := 0; i < 8; i++ {
+			if err := l.Log(&logger.Message{ContainerID: cid, Line: []byte("line" + strconv.Itoa(n+i)), Source: "src1"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		lines := readLines(t, watcher, 8)
+		for i, line := range lines {
+			if expected := "line" + strconv.Itoa(n+i) + "\n"; line != expected {
+				t.Fatalf("Expected %q, got %q", expected, line)
+			}
+		}
+		n += 8
+	}
+
+	l.(*JSONFileLogger).compressing.Wait()
+	if _, err := os.Stat(filename + ".2.gz"); err != nil {
+		t.Fatalf("Expected the logs to be rotated and compressed: %v", err)
+	}
+}
+
+func TestValidateLogOptCompress(t *testing.T) {
+	if err := ValidateLogOpt(map[string]string{"max-file": "2", "compress": "true"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateLogOpt(map[string]string{"compress": "true"}); err == nil {
+		t.Fatal("Expected an error when compress is set without max-file")
+	}
+	if err := ValidateLogOpt(map[string]string{"max-file": "2", "compress": "yes please"}); err == nil {
+		t.Fatal("Expected an error for an invalid compress value")
+	}
+}
@@ -0,0 +1,135 @@
+// Package localcache provides a Logger decorator which keeps a local copy
+// of the messages sent to a logging driver, so that `docker logs` works
+// for the drivers which cannot read messages back, like syslog or gelf.
+// The local copy is a json-file log rotated as a ring buffer: only the
+// most recent messages are kept.
+package localcache
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/jsonfilelog"
+	"github.com/docker/docker/pkg/units"
+)
+
+const (
+	// EnabledOpt is the log option enabling the local cache.
+	EnabledOpt = "cache"
+	// MaxSizeOpt is the log option setting the size a cache file can grow
+	// to before it is rotated.
+	MaxSizeOpt = "cache-max-size"
+	// MaxFileOpt is the log option setting the number of cache files kept.
+	MaxFileOpt = "cache-max-file"
+
+	defaultMaxSize = "10m"
+	defaultMaxFile = "3"
+)
+
+// loggerWithCache writes the messages both to a logging driver and to a
+// local json-file log, which messages are read back from.
+type loggerWithCache struct {
+	l     logger.Logger
+	cache *jsonfilelog.JSONFileLogger
+}
+
+// New wraps l with a local cache stored at path if the cache is enabled in
+// ctx.Config, and returns l unchanged otherwise. Loggers which can already
+// read messages back are not wrapped.
+func New(l logger.Logger, ctx logger.Context, path string) (logger.Logger, error) {
+	if !Enabled(ctx.Config) {
+		return l, nil
+	}
+	if _, ok := l.(logger.LogReader); ok {
+		return l, nil
+	}
+
+	cacheCtx := ctx
+	cacheCtx.LogPath = path
+	cacheCtx.Config = map[string]string{
+		"max-size": defaultMaxSize,
+		"max-file": defaultMaxFile,
+	}
+	if v, ok := ctx.Config[MaxSizeOpt]; ok {
+		cacheCtx.Config["max-size"] = v
+	}
+	if v, ok := ctx.Config[MaxFileOpt]; ok {
+		cacheCtx.Config["max-file"] = v
+	}
+
+	cache, err := jsonfilelog.New(cacheCtx)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating local log cache: %v", err)
+	}
+	return &loggerWithCache{l: l, cache: cache.(*jsonfilelog.JSONFileLogger)}, nil
+}
+
+// Log sends the message to the logging driver and keeps a copy of it in
+// the cache. A failure to write to the cache is not reported to the
+// caller, so the driver keeps receiving the messages.
+func (l *loggerWithCache) Log(msg *logger.Message) error {
+	if err := l.cache.Log(msg); err != nil {
+		logrus.Errorf("Error writing log message to the local cache of %s: %v", msg.ContainerID, err)
+	}
+	return l.l.Log(msg)
+}
+
+func (l *loggerWithCache) Name() string {
+	return l.l.Name()
+}
+
+// ReadLogs reads the messages back from the local cache.
+func (l *loggerWithCache) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
+	return l.cache.ReadLogs(config)
+}
+
+func (l *loggerWithCache) Close() error {
+	err := l.l.Close()
+	if cerr := l.cache.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Enabled returns true if the local cache is enabled in the log options.
+func Enabled(cfg map[string]string) bool {
+	enabled, _ := strconv.ParseBool(cfg[EnabledOpt])
+	return enabled
+}
+
+// DriverOpts returns the log options meant for the logging driver, that is
+// cfg without the options of the local cache.
+func DriverOpts(cfg map[string]string) map[string]string {
+	opts := make(map[string]string, len(cfg))
+	for k, v := range cfg {
+		switch k {
+		case EnabledOpt, MaxSizeOpt, MaxFileOpt:
+		default:
+			opts[k] = v
+		}
+	}
+	return opts
+}
+
+// ValidateLogOpts checks the options of the local cache, then the other
+// options against the logging driver with the given name.
+func ValidateLogOpts(name string, cfg map[string]string) error {
+	if v, ok := cfg[EnabledOpt]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("invalid value for log opt '%s': %s", EnabledOpt, v)
+		}
+	}
+	if v, ok := cfg[MaxSizeOpt]; ok {
+		if _, err := units.FromHumanSize(v); err != nil {
+			return fmt.Errorf("invalid value for log opt '%s': %s", MaxSizeOpt, v)
+		}
+	}
+	if v, ok := cfg[MaxFileOpt]; ok {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 {
+			return fmt.Errorf("invalid value for log opt '%s': %s", MaxFileOpt, v)
+		}
+	}
+	return logger.ValidateLogOpts(name, DriverOpts(cfg))
+}
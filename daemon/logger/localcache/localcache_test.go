@@ -0,0 +1,175 @@
+package localcache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+)
+
+type fakeLogger struct {
+	mu     sync.Mutex
+	msgs   []string
+	closed bool
+}
+
+func (l *fakeLogger) Log(msg *logger.Message) error {
+	l.mu.Lock()
+	l.msgs = append(l.msgs, string(msg.Line))
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *fakeLogger) Name() string { return "fake" }
+
+func (l *fakeLogger) Close() error {
+	l.closed = true
+	return nil
+}
+
+func readAll(t *testing.T, r logger.LogReader, config logger.ReadConfig) []string {
+	watcher := r.ReadLogs(config)
+	defer watcher.Close()
+	var lines []string
+	for {
+		select {
+		case msg, ok := <-watcher.Msg:
+			if !ok {
+				return lines
+			}
+			lines = append(lines, string(msg.Line))
+		case err := <-watcher.Err:
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timeout reading logs from the cache")
+		}
+	}
+}
+
+func TestLocalCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fake := &fakeLogger{}
+	ctx := logger.Context{
+		ContainerID: "container",
+		Config:      map[string]string{EnabledOpt: "true"},
+	}
+	l, err := New(fake, ctx, filepath.Join(dir, "container-cache.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Name() != "fake" {
+		t.Fatalf("Expected the name of the wrapped logger, got %s", l.Name())
+	}
+	r, ok := l.(logger.LogReader)
+	if !ok {
+		t.Fatal("Expected the cached logger to support reading")
+	}
+
+	now := time.Now().UTC()
+	for i, line := range []string{"line1", "line2", "line3"} {
+		msg := &logger.Message{ContainerID: "container", Line: []byte(line), Source: "stdout", Timestamp: now.Add(time.Duration(i) * time.Second)}
+		if err := l.Log(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(fake.msgs) != 3 {
+		t.Fatalf("Expected the messages to be sent to the driver, got %v", fake.msgs)
+	}
+
+	lines := readAll(t, r, logger.ReadConfig{Tail: -1})
+	if len(lines) != 3 || lines[0] != "line1\n" || lines[2] != "line3\n" {
+		t.Fatalf("Expected all the lines, got %q", lines)
+	}
+	lines = readAll(t, r, logger.ReadConfig{Tail: 1})
+	if len(lines) != 1 || lines[0] != "line3\n" {
+		t.Fatalf("Expected the last line, got %q", lines)
+	}
+	lines = readAll(t, r, logger.ReadConfig{Tail: -1, Since: now.Add(time.Second)})
+	if len(lines) != 2 || lines[0] != "line2\n" {
+		t.Fatalf("Expected the lines since line2, got %q", lines)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.closed {
+		t.Fatal("Expected the driver to be closed")
+	}
+}
+
+func TestLocalCacheRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := logger.Context{
+		ContainerID: "container",
+		Config:      map[string]string{EnabledOpt: "true", MaxSizeOpt: "1k", MaxFileOpt: "2"},
+	}
+	path := filepath.Join(dir, "container-cache.log")
+	l, err := New(&fakeLogger{}, ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for i := 0; i < 100; i++ {
+		if err := l.Log(&logger.Message{Line: []byte("a line long enough to fill the cache"), Source: "stdout", Timestamp: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := filepath.Glob(path + "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 cache files, got %v", files)
+	}
+	lines := readAll(t, l.(logger.LogReader), logger.ReadConfig{Tail: -1})
+	if len(lines) == 0 || len(lines) >= 100 {
+		t.Fatalf("Expected the oldest lines to be dropped, got %d lines", len(lines))
+	}
+}
+
+func TestLocalCacheDisabled(t *testing.T) {
+	fake := &fakeLogger{}
+	l, err := New(fake, logger.Context{Config: map[string]string{}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l != fake {
+		t.Fatal("Expected the logger not to be wrapped when the cache is disabled")
+	}
+}
+
+func TestValidateLogOpts(t *testing.T) {
+	valid := map[string]string{EnabledOpt: "true", MaxSizeOpt: "5m", MaxFileOpt: "2", "tag": "foo"}
+	if err := ValidateLogOpts("fake", valid); err != nil {
+		t.Fatal(err)
+	}
+	opts := DriverOpts(valid)
+	if len(opts) != 1 || opts["tag"] != "foo" {
+		t.Fatalf("Expected only the driver options, got %v", opts)
+	}
+
+	for _, cfg := range []map[string]string{
+		{EnabledOpt: "maybe"},
+		{MaxSizeOpt: "huge"},
+		{MaxFileOpt: "0"},
+	} {
+		if err := ValidateLogOpts("fake", cfg); err == nil {
+			t.Fatalf("Expected an error for %v", cfg)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/docker/pkg/stringid"
+)
+
+// extName is the name of the subsystem log driver plugins implement.
+const extName = "LogDriver"
+
+// logPlugin defines the available functions that logging plugins must implement.
+type logPlugin interface {
+	// StartLogging starts reading the log messages of a container from
+	// the FIFO at file.
+	StartLogging(file string, info Context) (err error)
+	// StopLogging is called once all the messages have been written to
+	// the FIFO at file.
+	StopLogging(file string) (err error)
+	// Capabilities returns the optional features the plugin implements.
+	Capabilities() (cap Capability, err error)
+	// ReadLogs streams back the messages logged for the container.
+	ReadLogs(info Context, config ReadConfig) (stream io.ReadCloser, err error)
+}
+
+// Capability defines the optional features a log driver plugin implements.
+type Capability struct {
+	// ReadLogs is true if the plugin implements LogDriver.ReadLogs, so
+	// that `docker logs` works for the containers logging to it.
+	ReadLogs bool
+}
+
+// getPlugin looks up a log driver plugin with the given name and returns a
+// Creator for the loggers sending messages to it.
+func getPlugin(name string) (Creator, error) {
+	pl, err := plugins.Get(name, extName)
+	if err != nil {
+		return nil, fmt.Errorf("Error looking up logging plugin %s: %v", name, err)
+	}
+
+	d := &logPluginProxy{pl.Client}
+	return makePluginCreator(name, d, pluginFifoBasePath), nil
+}
+
+func makePluginCreator(name string, l logPlugin, basePath string) Creator {
+	return func(ctx Context) (Logger, error) {
+		// A container can have several loggers at once, for example
+		// while its logs are read after it stopped, so every logger
+		// gets a FIFO of its own.
+		path := filepath.Join(basePath, ctx.ContainerID+"-"+stringid.GenerateNonCryptoID())
+
+		a := &pluginAdapter{
+			driverName: name,
+			id:         ctx.ContainerID,
+			info:       ctx,
+			plugin:     l,
+			fifoPath:   path,
+		}
+
+		caps, err := l.Capabilities()
+		if err != nil {
+			logrus.Debugf("Logging plugin %s does not report its capabilities: %v", name, err)
+		}
+
+		stream, err := openPluginStream(path)
+		if err != nil {
+			return nil, err
+		}
+		a.stream = stream
+		a.enc = json.NewEncoder(stream)
+
+		if err := l.StartLogging(path, ctx); err != nil {
+			stream.Close()
+			os.Remove(path)
+			return nil, fmt.Errorf("Error starting logging with plugin %s: %v", name, err)
+		}
+
+		if caps.ReadLogs {
+			return &pluginAdapterWithRead{a}, nil
+		}
+		return a, nil
+	}
+}
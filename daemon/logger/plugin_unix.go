@@ -0,0 +1,32 @@
+// +build !windows
+
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// pluginFifoBasePath is the directory the FIFOs streaming the messages of
+// the containers to the log driver plugins are created in.
+var pluginFifoBasePath = "/run/docker/logging"
+
+// openPluginStream creates the FIFO at path and opens it for writing.
+func openPluginStream(path string) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := syscall.Mkfifo(path, 0700); err != nil {
+		return nil, err
+	}
+	// Opening the FIFO for reading as well does not block until the
+	// plugin opens it, and keeps it open if the plugin closes its end.
+	f, err := os.OpenFile(path, os.O_RDWR, 0700)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,156 @@
+// +build !windows
+
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/docker/pkg/tlsconfig"
+)
+
+const pluginMimetype = "application/vnd.docker.plugins.v1+json"
+
+// fakeLogPlugin records the entries it reads from the FIFOs of the daemon.
+type fakeLogPlugin struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	done    chan struct{}
+}
+
+func (p *fakeLogPlugin) handle(mux *http.ServeMux, t *testing.T) {
+	mux.HandleFunc("/LogDriver.Capabilities", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", pluginMimetype)
+		json.NewEncoder(w).Encode(map[string]interface{}{"Cap": Capability{ReadLogs: true}})
+	})
+
+	mux.HandleFunc("/LogDriver.StartLogging", func(w http.ResponseWriter, r *http.Request) {
+		var req logPluginProxyStartLoggingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Info.ContainerID != "container" {
+			t.Fatalf("Expected logging info for container, got %s", req.Info.ContainerID)
+		}
+		f, err := os.OpenFile(req.File, os.O_RDONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			defer close(p.done)
+			defer f.Close()
+			dec := json.NewDecoder(f)
+			for {
+				var e LogEntry
+				if err := dec.Decode(&e); err != nil {
+					return
+				}
+				p.mu.Lock()
+				p.entries = append(p.entries, e)
+				p.mu.Unlock()
+			}
+		}()
+		w.Header().Set("Content-Type", pluginMimetype)
+		w.Write([]byte("{}"))
+	})
+
+	mux.HandleFunc("/LogDriver.StopLogging", func(w http.ResponseWriter, r *http.Request) {
+		<-p.done
+		w.Header().Set("Content-Type", pluginMimetype)
+		w.Write([]byte("{}"))
+	})
+
+	mux.HandleFunc("/LogDriver.ReadLogs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", pluginMimetype)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		enc := json.NewEncoder(w)
+		for _, e := range p.entries {
+			enc.Encode(e)
+		}
+	})
+}
+
+func TestPluginLogger(t *testing.T) {
+	base, err := ioutil.TempDir("", "logger-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	p := &fakeLogPlugin{done: make(chan struct{})}
+	p.handle(mux, t)
+
+	c, err := plugins.NewClient(server.URL, tlsconfig.Options{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	creator := makePluginCreator("fake", &logPluginProxy{c}, base)
+
+	l, err := creator(Context{ContainerID: "container"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Name() != "fake" {
+		t.Fatalf("Expected logger name fake, got %s", l.Name())
+	}
+	reader, ok := l.(LogReader)
+	if !ok {
+		t.Fatal("Expected the logger to support reading")
+	}
+
+	now := time.Now()
+	lines := []string{"line1", "line2", "line3"}
+	for i, line := range lines {
+		msg := &Message{ContainerID: "container", Line: []byte(line), Source: "stdout", Timestamp: now.Add(time.Duration(i) * time.Second)}
+		if err := l.Log(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	files, err := ioutil.ReadDir(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("Expected the FIFO to be removed, got %v", files)
+	}
+
+	watcher := reader.ReadLogs(ReadConfig{Since: now.Add(time.Second)})
+	defer watcher.Close()
+	var read []string
+	for {
+		select {
+		case msg, ok := <-watcher.Msg:
+			if !ok {
+				if len(read) != 2 || read[0] != "line2\n" || read[1] != "line3\n" {
+					t.Fatalf("Expected line2 and line3, got %v", read)
+				}
+				return
+			}
+			if msg.Source != "stdout" {
+				t.Fatalf("Expected source stdout, got %s", msg.Source)
+			}
+			read = append(read, string(msg.Line))
+		case err := <-watcher.Err:
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timeout reading logs from the plugin")
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// +build windows
+
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+var pluginFifoBasePath = ""
+
+func openPluginStream(path string) (io.WriteCloser, error) {
+	return nil, errors.New("log driver plugins are not supported on this platform")
+}
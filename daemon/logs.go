@@ -36,6 +36,10 @@ func (daemon *Daemon) ContainerLogs(container *Container, config *ContainerLogsC
 	if err != nil {
 		return err
 	}
+	if cLog != container.logDriver {
+		// the logger was created only to read the logs
+		defer cLog.Close()
+	}
 	logReader, ok := cLog.(logger.LogReader)
 	if !ok {
 		return logger.ErrReadLogsNotSupported
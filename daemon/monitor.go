@@ -3,6 +3,7 @@ package daemon
 import (
 	"io"
 	"os/exec"
+	"strconv"
 	"sync"
 	"time"
 
@@ -10,10 +11,12 @@ import (
 	"github.com/docker/docker/daemon/execdriver"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/runconfig"
+	"github.com/opencontainers/runc/libcontainer"
 )
 
 const (
-	defaultTimeIncrement = 100
+	defaultTimeIncrement = 100 * time.Millisecond
+	defaultResetWindow   = 10 * time.Second
 	loggerCloseTimeout   = 10 * time.Second
 )
 
@@ -48,8 +51,7 @@ type containerMonitor struct {
 	stopChan chan struct{}
 
 	// timeIncrement is the amount of time to wait between restarts
-	// this is in milliseconds
-	timeIncrement int
+	timeIncrement time.Duration
 
 	// lastStartTime is the time which the monitor last exec'd the container's process
 	lastStartTime time.Time
@@ -102,6 +104,33 @@ func (m *containerMonitor) Close() error {
 
 // Start starts the containers process and monitors it according to the restart policy
 func (m *containerMonitor) Start() error {
+	return m.start(nil)
+}
+
+// Restore restores the containers process from a checkpoint and monitors it
+// like a started process. When the restart policy restarts the container, its
+// process is started again rather than restored.
+func (m *containerMonitor) Restore(opts *libcontainer.CriuOpts) error {
+	return m.start(func(pipes *execdriver.Pipes) (execdriver.ExitStatus, error) {
+		m.container.LogEvent("restore")
+		return m.container.daemon.Restore(m.container, pipes, m.callback, opts)
+	})
+}
+
+// Reattach attaches to the process of a container kept running by live
+// restore while the daemon was stopped and monitors it like a started
+// process.
+func (m *containerMonitor) Reattach() error {
+	restartCount := m.container.RestartCount
+	return m.start(func(pipes *execdriver.Pipes) (execdriver.ExitStatus, error) {
+		m.container.RestartCount = restartCount
+		return m.container.daemon.Reattach(m.container, pipes, m.reattachCallback)
+	})
+}
+
+// start runs the process of the container, with first for the first run
+// when it is set, and restarts it according to the restart policy.
+func (m *containerMonitor) start(first func(*execdriver.Pipes) (execdriver.ExitStatus, error)) error {
 	var (
 		err        error
 		exitStatus execdriver.ExitStatus
@@ -110,6 +139,15 @@ func (m *containerMonitor) Start() error {
 		afterRun bool
 	)
 
+	// containers created with --rm are removed once they are stopped for
+	// good and cleaned up. A failure of the first start is handled by the
+	// caller.
+	defer func() {
+		if afterRun && m.container.shouldAutoRemove() {
+			m.container.daemon.autoRemove(m.container)
+		}
+	}()
+
 	// ensure that when the monitor finally exits we release the networking and unmount the rootfs
 	defer func() {
 		if afterRun {
@@ -134,14 +172,19 @@ func (m *containerMonitor) Start() error {
 
 		pipes := execdriver.NewPipes(m.container.stdin, m.container.stdout, m.container.stderr, m.container.Config.OpenStdin)
 
-		m.container.LogEvent("start")
-
 		m.lastStartTime = time.Now()
 
-		if exitStatus, err = m.container.daemon.Run(m.container, pipes, m.callback); err != nil {
+		if first != nil {
+			exitStatus, err = first(pipes)
+			first = nil
+		} else {
+			m.container.LogEvent("start")
+			exitStatus, err = m.container.daemon.Run(m.container, pipes, m.callback)
+		}
+		if err != nil {
 			// if we receive an internal error from the initial start of a container then lets
 			// return it instead of entering the restart loop
-			if m.container.RestartCount == 0 {
+			if !afterRun {
 				m.container.ExitCode = -1
 				m.resetContainer(false)
 
@@ -154,6 +197,10 @@ func (m *containerMonitor) Start() error {
 		// here container.Lock is already lost
 		afterRun = true
 
+		m.container.Lock()
+		m.container.daemon.stopHealthchecks(m.container)
+		m.container.Unlock()
+
 		m.resetMonitor(err == nil && exitStatus.ExitCode == 0)
 
 		if m.shouldRestart(exitStatus.ExitCode) {
@@ -161,7 +208,7 @@ func (m *containerMonitor) Start() error {
 			if exitStatus.OOMKilled {
 				m.container.LogEvent("oom")
 			}
-			m.container.LogEvent("die")
+			m.logEvent("die", exitStatus.ExitCode)
 			m.resetContainer(true)
 
 			// sleep with a small time increment between each restart to help avoid issues cased by quickly
@@ -178,7 +225,7 @@ func (m *containerMonitor) Start() error {
 		if exitStatus.OOMKilled {
 			m.container.LogEvent("oom")
 		}
-		m.container.LogEvent("die")
+		m.logEvent("die", exitStatus.ExitCode)
 		m.resetContainer(true)
 		return err
 	}
@@ -186,16 +233,26 @@ func (m *containerMonitor) Start() error {
 
 // resetMonitor resets the stateful fields on the containerMonitor based on the
 // previous runs success or failure.  Regardless of success, if the container had
-// an execution time of more than 10s then reset the timer back to the default
+// an execution time longer than the reset window of the restart policy, 10s by
+// default, then reset the timer back to the default
 func (m *containerMonitor) resetMonitor(successful bool) {
-	executionTime := time.Now().Sub(m.lastStartTime).Seconds()
+	executionTime := time.Now().Sub(m.lastStartTime)
+
+	resetWindow := m.restartPolicy.ResetWindow
+	if resetWindow == 0 {
+		resetWindow = defaultResetWindow
+	}
 
-	if executionTime > 10 {
+	if executionTime > resetWindow {
 		m.timeIncrement = defaultTimeIncrement
 	} else {
 		// otherwise we need to increment the amount of time we wait before restarting
-		// the process.  We will build up by multiplying the increment by 2
+		// the process.  We will build up by multiplying the increment by 2, up to the
+		// maximum backoff of the restart policy
 		m.timeIncrement *= 2
+		if max := m.restartPolicy.MaximumBackoff; max != 0 && m.timeIncrement > max {
+			m.timeIncrement = max
+		}
 	}
 
 	// the container exited successfully so we need to reset the failure counter
@@ -210,7 +267,7 @@ func (m *containerMonitor) resetMonitor(successful bool) {
 // a user or docker asks for the container to be stopped
 func (m *containerMonitor) waitForNextRestart() {
 	select {
-	case <-time.After(time.Duration(m.timeIncrement) * time.Millisecond):
+	case <-time.After(m.timeIncrement):
 	case <-m.stopChan:
 	}
 }
@@ -227,7 +284,9 @@ func (m *containerMonitor) shouldRestart(exitCode int) bool {
 	}
 
 	switch {
-	case m.restartPolicy.IsAlways():
+	case m.restartPolicy.IsAlways(), m.restartPolicy.IsUnlessStopped():
+		// a container stopped by the user is not restarted, the monitor
+		// has been told to stop above
 		return true
 	case m.restartPolicy.IsOnFailure():
 		// the default value of 0 for MaximumRetryCount means that we will not enforce a maximum count
@@ -256,6 +315,20 @@ func (m *containerMonitor) callback(processConfig *execdriver.ProcessConfig, pid
 	}
 
 	m.container.setRunning(pid)
+	m.started()
+}
+
+// reattachCallback updates the state of a container reattached to its
+// process, which kept running since it was started.
+func (m *containerMonitor) reattachCallback(processConfig *execdriver.ProcessConfig, pid int) {
+	m.container.Pid = pid
+	m.started()
+}
+
+// started monitors the health of the container once its process runs and
+// signals that it started.
+func (m *containerMonitor) started() {
+	m.container.daemon.initHealthMonitor(m.container)
 
 	// signal that the process has started
 	// close channel only if not closed
@@ -337,3 +410,11 @@ func (m *containerMonitor) resetContainer(lock bool) {
 		SysProcAttr: c.SysProcAttr,
 	}
 }
+
+// logEvent generates an event for the container with its exit code.
+func (m *containerMonitor) logEvent(action string, exitCode int) {
+	attributes := map[string]string{
+		"exitCode": strconv.Itoa(exitCode),
+	}
+	m.container.daemon.LogContainerEventWithAttributes(m.container, action, attributes)
+}
@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/runconfig"
+)
+
+func newMonitorTestContainer(policy runconfig.RestartPolicy) *Container {
+	return &Container{
+		CommonContainer: CommonContainer{
+			ID:         "container_id",
+			State:      NewState(),
+			hostConfig: &runconfig.HostConfig{RestartPolicy: policy},
+		},
+	}
+}
+
+func TestMonitorShouldRestart(t *testing.T) {
+	cases := []struct {
+		policy   runconfig.RestartPolicy
+		exitCode int
+		failures int
+		stopped  bool
+		restart  bool
+	}{
+		{policy: runconfig.RestartPolicy{Name: "no"}, exitCode: 1, restart: false},
+		{policy: runconfig.RestartPolicy{Name: "always"}, exitCode: 0, restart: true},
+		{policy: runconfig.RestartPolicy{Name: "always"}, exitCode: 0, stopped: true, restart: false},
+		{policy: runconfig.RestartPolicy{Name: "unless-stopped"}, exitCode: 0, restart: true},
+		{policy: runconfig.RestartPolicy{Name: "unless-stopped"}, exitCode: 1, stopped: true, restart: false},
+		{policy: runconfig.RestartPolicy{Name: "on-failure"}, exitCode: 0, restart: false},
+		{policy: runconfig.RestartPolicy{Name: "on-failure"}, exitCode: 1, failures: 10, restart: true},
+		{policy: runconfig.RestartPolicy{Name: "on-failure", MaximumRetryCount: 2}, exitCode: 1, failures: 2, restart: true},
+		{policy: runconfig.RestartPolicy{Name: "on-failure", MaximumRetryCount: 2}, exitCode: 1, failures: 3, restart: false},
+	}
+	for _, c := range cases {
+		m := newContainerMonitor(newMonitorTestContainer(c.policy), c.policy)
+		m.failureCount = c.failures
+		if c.stopped {
+			m.ExitOnNext()
+		}
+		if restart := m.shouldRestart(c.exitCode); restart != c.restart {
+			t.Fatalf("Expected restart to be %v for %+v, got %v", c.restart, c, restart)
+		}
+	}
+}
+
+func TestMonitorBackoff(t *testing.T) {
+	policy := runconfig.RestartPolicy{Name: "always", MaximumBackoff: 500 * time.Millisecond}
+	m := newContainerMonitor(newMonitorTestContainer(policy), policy)
+
+	// quick exits double the delay up to the maximum backoff
+	for _, expected := range []time.Duration{200, 400, 500, 500} {
+		m.lastStartTime = time.Now()
+		m.resetMonitor(false)
+		if m.timeIncrement != expected*time.Millisecond {
+			t.Fatalf("Expected a delay of %dms, got %s", expected, m.timeIncrement)
+		}
+	}
+	if m.failureCount != 4 {
+		t.Fatalf("Expected 4 failures, got %d", m.failureCount)
+	}
+
+	// running longer than the default reset window resets the delay
+	m.lastStartTime = time.Now().Add(-11 * time.Second)
+	m.resetMonitor(true)
+	if m.timeIncrement != defaultTimeIncrement || m.failureCount != 0 {
+		t.Fatalf("Expected the delay and failures to be reset, got %s and %d", m.timeIncrement, m.failureCount)
+	}
+}
+
+func TestMonitorResetWindow(t *testing.T) {
+	policy := runconfig.RestartPolicy{Name: "always", ResetWindow: time.Minute}
+	m := newContainerMonitor(newMonitorTestContainer(policy), policy)
+
+	m.lastStartTime = time.Now().Add(-30 * time.Second)
+	m.resetMonitor(true)
+	if m.timeIncrement != 2*defaultTimeIncrement {
+		t.Fatalf("Expected the delay to grow within the reset window, got %s", m.timeIncrement)
+	}
+
+	m.lastStartTime = time.Now().Add(-2 * time.Minute)
+	m.resetMonitor(true)
+	if m.timeIncrement != defaultTimeIncrement {
+		t.Fatalf("Expected the delay to be reset after the reset window, got %s", m.timeIncrement)
+	}
+}
+
+func TestContainerShouldRestartUnlessStopped(t *testing.T) {
+	c := newMonitorTestContainer(runconfig.RestartPolicy{Name: "unless-stopped"})
+	if !c.shouldRestart() {
+		t.Fatal("Expected the container to be restarted with the daemon")
+	}
+	c.SetManuallyStopped()
+	if c.shouldRestart() {
+		t.Fatal("Expected a container stopped by the user not to be restarted")
+	}
+	c.SetRunning(1)
+	if c.HasBeenManuallyStopped {
+		t.Fatal("Expected starting the container to reset the manual stop")
+	}
+}
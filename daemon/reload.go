@@ -0,0 +1,127 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/logger/localcache"
+	"github.com/docker/docker/registry"
+	"github.com/docker/docker/runconfig"
+)
+
+// Reload applies the options of config and registryOptions listed in
+// options to the running daemon, ignoring the ones that are not in
+// ReloadableOptions. An option listed but absent from config is reset to
+// its default. Nothing is applied when one of the values is invalid. A
+// reload event is logged with the new values of the options that changed.
+func (daemon *Daemon) Reload(config *Config, registryOptions *registry.Options, options []string) error {
+	apply := make(map[string]bool)
+	for _, o := range options {
+		apply[o] = true
+	}
+
+	daemon.configLock.Lock()
+	defer daemon.configLock.Unlock()
+
+	changed := make(map[string]string)
+	if apply["label"] && !equalStrings(daemon.config.Labels, config.Labels) {
+		changed["label"] = strings.Join(config.Labels, ",")
+	}
+	if apply["debug"] && daemon.config.Debug != config.Debug {
+		changed["debug"] = fmt.Sprint(config.Debug)
+	}
+	if apply["log-opt"] && !equalLogOpts(daemon.defaultLogConfig.Config, config.LogConfig.Config) {
+		if err := localcache.ValidateLogOpts(daemon.defaultLogConfig.Type, config.LogConfig.Config); err != nil {
+			return fmt.Errorf("Failed to set log opts: %v", err)
+		}
+		var opts []string
+		for k, v := range config.LogConfig.Config {
+			opts = append(opts, k+"="+v)
+		}
+		sort.Strings(opts)
+		changed["log-opt"] = strings.Join(opts, ",")
+	}
+	mirrors := daemon.RegistryService.Mirrors()
+	if apply["registry-mirror"] && !equalStrings(mirrors, registryOptions.Mirrors.GetAll()) {
+		mirrors = registryOptions.Mirrors.GetAll()
+		changed["registry-mirror"] = strings.Join(mirrors, ",")
+	}
+	insecureRegistries := daemon.RegistryService.InsecureRegistries()
+	if apply["insecure-registry"] && !equalStrings(insecureRegistries, registryOptions.InsecureRegistries.GetAll()) {
+		insecureRegistries = registryOptions.InsecureRegistries.GetAll()
+		changed["insecure-registry"] = strings.Join(insecureRegistries, ",")
+	}
+
+	if _, ok := changed["label"]; ok {
+		daemon.config.Labels = append([]string{}, config.Labels...)
+	}
+	if _, ok := changed["debug"]; ok {
+		daemon.config.Debug = config.Debug
+		if config.Debug {
+			os.Setenv("DEBUG", "1")
+			logrus.SetLevel(logrus.DebugLevel)
+		} else {
+			os.Unsetenv("DEBUG")
+			logrus.SetLevel(logrus.InfoLevel)
+		}
+	}
+	if _, ok := changed["log-opt"]; ok {
+		logConfig := daemon.defaultLogConfig
+		logConfig.Config = make(map[string]string)
+		for k, v := range config.LogConfig.Config {
+			logConfig.Config[k] = v
+		}
+		daemon.defaultLogConfig = logConfig
+	}
+	_, mirrorsChanged := changed["registry-mirror"]
+	_, insecureChanged := changed["insecure-registry"]
+	if mirrorsChanged || insecureChanged {
+		daemon.RegistryService.Reload(mirrors, insecureRegistries)
+	}
+
+	logrus.Infof("Reloaded the daemon configuration: %v", changed)
+	daemon.LogDaemonEventWithAttributes("reload", changed)
+	return nil
+}
+
+// getDefaultLogConfig returns the log configuration of the containers that
+// do not set one.
+func (daemon *Daemon) getDefaultLogConfig() runconfig.LogConfig {
+	daemon.configLock.RLock()
+	defer daemon.configLock.RUnlock()
+	return daemon.defaultLogConfig
+}
+
+// getLabels returns the labels of the daemon.
+func (daemon *Daemon) getLabels() []string {
+	daemon.configLock.RLock()
+	defer daemon.configLock.RUnlock()
+	return daemon.config.Labels
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalLogOpts(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
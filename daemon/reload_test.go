@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"testing"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/registry"
+	"github.com/docker/docker/runconfig"
+)
+
+func TestReload(t *testing.T) {
+	daemon := &Daemon{
+		ID:               "daemon-id",
+		config:           &Config{CommonConfig: CommonConfig{Labels: []string{"a=b"}}},
+		defaultLogConfig: runconfig.LogConfig{Type: "json-file", Config: map[string]string{}},
+		RegistryService:  registry.NewService(nil),
+		EventsService:    events.New(),
+	}
+	_, l := daemon.EventsService.Subscribe()
+	defer daemon.EventsService.Evict(l)
+
+	config, flags := newConfigFlags()
+	registryOptions := &registry.Options{}
+	registryOptions.InstallFlags(flags, func(string) string { return "" })
+	for _, arg := range [][]string{
+		{"-label", "c=d"},
+		{"-log-opt", "max-size=10m"},
+		{"-registry-mirror", "https://mirror.example.com"},
+		{"-insecure-registry", "registry.example.com"},
+	} {
+		if err := flags.Set(arg[0], arg[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the labels and the mirrors are left alone
+	if err := daemon.Reload(config, registryOptions, []string{"log-opt", "insecure-registry"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(daemon.getLabels()) != 1 || daemon.getLabels()[0] != "a=b" {
+		t.Fatalf("Expected the labels not to be reloaded, got %v", daemon.getLabels())
+	}
+	if logConfig := daemon.getDefaultLogConfig(); logConfig.Type != "json-file" || logConfig.Config["max-size"] != "10m" {
+		t.Fatalf("Expected the log options to be reloaded, got %v", logConfig)
+	}
+	serviceConfig := daemon.RegistryService.ServiceConfig()
+	if len(serviceConfig.Mirrors) != 0 || serviceConfig.IndexConfigs["registry.example.com"] == nil {
+		t.Fatalf("Expected only the insecure registries to be reloaded, got %+v", serviceConfig)
+	}
+
+	ev := (<-l).(eventtypes.Message)
+	if ev.Type != eventtypes.DaemonEventType || ev.Action != "reload" || ev.Actor.ID != "daemon-id" {
+		t.Fatalf("Unexpected event %+v", ev)
+	}
+	if len(ev.Actor.Attributes) != 2 || ev.Actor.Attributes["log-opt"] != "max-size=10m" || ev.Actor.Attributes["insecure-registry"] != "registry.example.com" {
+		t.Fatalf("Expected the changed options in the event, got %v", ev.Actor.Attributes)
+	}
+
+	// invalid log options are rejected before anything is applied
+	config.LogConfig.Config["unknown"] = "value"
+	if err := daemon.Reload(config, registryOptions, ReloadableOptions); err == nil {
+		t.Fatal("Expected an error for an invalid log option")
+	}
+	if len(daemon.getLabels()) != 1 || len(daemon.RegistryService.Mirrors()) != 0 {
+		t.Fatal("Expected nothing to be reloaded after an error")
+	}
+}
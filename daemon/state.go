@@ -11,18 +11,22 @@ import (
 
 type State struct {
 	sync.Mutex
-	Running           bool
-	Paused            bool
-	Restarting        bool
-	OOMKilled         bool
-	removalInProgress bool // Not need for this to be persistent on disk.
-	Dead              bool
-	Pid               int
-	ExitCode          int
-	Error             string // contains last known error when starting the container
-	StartedAt         time.Time
-	FinishedAt        time.Time
-	waitChan          chan struct{}
+	Running                bool
+	Paused                 bool
+	Restarting             bool
+	OOMKilled              bool
+	removalInProgress      bool // Not need for this to be persistent on disk.
+	Dead                   bool
+	Checkpointed           bool // Whether the processes were stopped by a checkpoint and can be restored
+	HasBeenManuallyStopped bool // Whether the container was stopped by the user rather than by the daemon
+	Pid                    int
+	ExitCode               int
+	Error                  string // contains last known error when starting the container
+	StartedAt              time.Time
+	FinishedAt             time.Time
+	CheckpointedAt         time.Time
+	Health                 *Health `json:",omitempty"`
+	waitChan               chan struct{}
 }
 
 func NewState() *State {
@@ -41,6 +45,10 @@ func (s *State) String() string {
 			return fmt.Sprintf("Restarting (%d) %s ago", s.ExitCode, units.HumanDuration(time.Now().UTC().Sub(s.FinishedAt)))
 		}
 
+		if h := s.Health; h != nil {
+			return fmt.Sprintf("Up %s (%s)", units.HumanDuration(time.Now().UTC().Sub(s.StartedAt)), h.String())
+		}
+
 		return fmt.Sprintf("Up %s", units.HumanDuration(time.Now().UTC().Sub(s.StartedAt)))
 	}
 
@@ -52,6 +60,10 @@ func (s *State) String() string {
 		return "Dead"
 	}
 
+	if s.Checkpointed {
+		return fmt.Sprintf("Checkpointed %s ago", units.HumanDuration(time.Now().UTC().Sub(s.CheckpointedAt)))
+	}
+
 	if s.StartedAt.IsZero() {
 		return "Created"
 	}
@@ -79,6 +91,10 @@ func (s *State) StateString() string {
 		return "dead"
 	}
 
+	if s.Checkpointed {
+		return "checkpointed"
+	}
+
 	if s.StartedAt.IsZero() {
 		return "created"
 	}
@@ -92,6 +108,7 @@ func isValidStateString(s string) bool {
 		s != "running" &&
 		s != "dead" &&
 		s != "created" &&
+		s != "checkpointed" &&
 		s != "exited" {
 		return false
 	}
@@ -179,6 +196,8 @@ func (s *State) setRunning(pid int) {
 	s.Running = true
 	s.Paused = false
 	s.Restarting = false
+	s.Checkpointed = false
+	s.HasBeenManuallyStopped = false
 	s.ExitCode = 0
 	s.Pid = pid
 	s.StartedAt = time.Now().UTC()
@@ -253,6 +272,15 @@ func (s *State) IsPaused() bool {
 	return res
 }
 
+// SetManuallyStopped records that the container is stopped by the user, so
+// that the "unless-stopped" restart policy does not start it again when the
+// daemon restarts.
+func (s *State) SetManuallyStopped() {
+	s.Lock()
+	s.HasBeenManuallyStopped = true
+	s.Unlock()
+}
+
 func (s *State) SetRemovalInProgress() error {
 	s.Lock()
 	defer s.Unlock()
@@ -269,6 +297,22 @@ func (s *State) ResetRemovalInProgress() {
 	s.Unlock()
 }
 
+// SetCheckpointed marks the container as checkpointed, its processes can be
+// restored from the checkpoint.
+func (s *State) SetCheckpointed() {
+	s.Lock()
+	s.Checkpointed = true
+	s.CheckpointedAt = time.Now().UTC()
+	s.Unlock()
+}
+
+func (s *State) IsCheckpointed() bool {
+	s.Lock()
+	res := s.Checkpointed
+	s.Unlock()
+	return res
+}
+
 func (s *State) SetDead() {
 	s.Lock()
 	s.Dead = true
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -102,3 +103,23 @@ func TestStateTimeoutWait(t *testing.T) {
 	}
 
 }
+
+func TestStateCheckpointed(t *testing.T) {
+	s := NewState()
+	s.SetRunning(42)
+	s.SetStopped(&execdriver.ExitStatus{ExitCode: 137})
+	s.SetCheckpointed()
+	if !s.IsCheckpointed() {
+		t.Fatal("State not checkpointed")
+	}
+	if s.StateString() != "checkpointed" {
+		t.Fatalf("Expected state checkpointed, got %s", s.StateString())
+	}
+	if !strings.HasPrefix(s.String(), "Checkpointed") {
+		t.Fatalf("Expected a checkpointed status, got %s", s.String())
+	}
+	s.SetRunning(43)
+	if s.IsCheckpointed() {
+		t.Fatal("Expected the checkpoint to be cleared when the container runs again")
+	}
+}
@@ -57,6 +57,9 @@ func convertStatsToAPITypes(ls *libcontainer.Stats) *types.Stats {
 			Stats:    mem.Stats,
 			Failcnt:  mem.Usage.Failcnt,
 		}
+		s.PidsStats = types.PidsStats{
+			Current: cs.PidsStats.Current,
+		}
 	}
 
 	return s
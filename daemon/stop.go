@@ -10,6 +10,7 @@ func (daemon *Daemon) ContainerStop(name string, seconds int) error {
 	if !container.IsRunning() {
 		return fmt.Errorf("Container already stopped")
 	}
+	container.SetManuallyStopped()
 	if err := container.Stop(seconds); err != nil {
 		return fmt.Errorf("Cannot stop container %s: %s\n", name, err)
 	}
@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/runconfig"
+)
+
+// ContainerUpdate changes the resources of a container. They are applied to
+// the container right away when it is running, and saved with its host
+// config so that they are kept when it is restarted.
+func (daemon *Daemon) ContainerUpdate(name string, updateConfig *runconfig.UpdateConfig) ([]string, error) {
+	container, err := daemon.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	container.Lock()
+	defer container.Unlock()
+
+	hostConfig := *container.hostConfig
+	mergeUpdateConfig(&hostConfig, updateConfig)
+	warnings, err := daemon.verifyContainerSettings(&hostConfig, nil)
+	if err != nil {
+		return warnings, err
+	}
+
+	if err := container.updateResources(&hostConfig); err != nil {
+		return warnings, fmt.Errorf("Cannot update container %s: %v", name, err)
+	}
+	container.LogEvent("update")
+	return warnings, nil
+}
+
+// mergeUpdateConfig sets the resources given in updateConfig on hostConfig.
+func mergeUpdateConfig(hostConfig *runconfig.HostConfig, updateConfig *runconfig.UpdateConfig) {
+	if updateConfig.Memory != 0 {
+		hostConfig.Memory = updateConfig.Memory
+	}
+	if updateConfig.MemorySwap != 0 {
+		hostConfig.MemorySwap = updateConfig.MemorySwap
+	}
+	if updateConfig.CPUShares != 0 {
+		hostConfig.CPUShares = updateConfig.CPUShares
+	}
+	if updateConfig.CPUPeriod != 0 {
+		hostConfig.CPUPeriod = updateConfig.CPUPeriod
+	}
+	if updateConfig.CPUQuota != 0 {
+		hostConfig.CPUQuota = updateConfig.CPUQuota
+	}
+	if updateConfig.CpusetCpus != "" {
+		hostConfig.CpusetCpus = updateConfig.CpusetCpus
+	}
+	if updateConfig.CpusetMems != "" {
+		hostConfig.CpusetMems = updateConfig.CpusetMems
+	}
+	if updateConfig.BlkioWeight != 0 {
+		hostConfig.BlkioWeight = updateConfig.BlkioWeight
+	}
+}
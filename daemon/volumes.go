@@ -8,14 +8,24 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/parsers/filters"
+	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/volume"
+	"github.com/docker/docker/volume/store"
 )
 
-// ErrVolumeReadonly is used to signal an error when trying to copy data into
-// a volume mount that is not writable.
-var ErrVolumeReadonly = errors.New("mounted volume is marked read-only")
+var (
+	// ErrVolumeReadonly is used to signal an error when trying to copy data into
+	// a volume mount that is not writable.
+	ErrVolumeReadonly = errors.New("mounted volume is marked read-only")
+
+	acceptedVolumeFilterTags = map[string]struct{}{
+		"dangling": {},
+	}
+)
 
 // mountPoint is the intersection point between a volume and a container. It
 // specifies which volume is to be used and where inside a container it should
@@ -92,3 +102,98 @@ func copyExistingContents(source, destination string) error {
 	}
 	return copyOwnership(source, destination)
 }
+
+// volumeToAPIType converts a volume.Volume to the type used by the remote API
+func volumeToAPIType(v volume.Volume) *types.Volume {
+	return &types.Volume{
+		Name:       v.Name(),
+		Driver:     v.DriverName(),
+		Mountpoint: v.Path(),
+	}
+}
+
+// VolumeCreate creates a volume with the specified name and driver. If no
+// name is given, a random one is generated.
+func (daemon *Daemon) VolumeCreate(name, driverName string) (*types.Volume, error) {
+	if name == "" {
+		name = stringid.GenerateNonCryptoID()
+	}
+
+	v, err := daemon.volumes.Create(name, driverName)
+	if err != nil {
+		return nil, err
+	}
+	daemon.LogVolumeEvent(v.Name(), "create", map[string]string{"driver": v.DriverName()})
+	return volumeToAPIType(v), nil
+}
+
+// VolumeInspect looks up a volume by name. An error is returned if the
+// volume cannot be found.
+func (daemon *Daemon) VolumeInspect(name string) (*types.Volume, error) {
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		if err == store.ErrNoSuchVolume {
+			return nil, fmt.Errorf("No such volume: %s", name)
+		}
+		return nil, err
+	}
+	return volumeToAPIType(v), nil
+}
+
+// VolumeRm removes the volume with the given name. Volumes that are still
+// referenced by a container cannot be removed.
+func (daemon *Daemon) VolumeRm(name string) error {
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		if err == store.ErrNoSuchVolume {
+			return fmt.Errorf("No such volume: %s", name)
+		}
+		return err
+	}
+	if err := daemon.volumes.Remove(v); err != nil {
+		if err == store.ErrVolumeInUse {
+			return fmt.Errorf("Conflict: remove %s: %v", name, err)
+		}
+		return fmt.Errorf("Error while removing volume %s: %v", name, err)
+	}
+	daemon.LogVolumeEvent(v.Name(), "destroy", map[string]string{"driver": v.DriverName()})
+	return nil
+}
+
+// Volumes lists known volumes, using the filter to restrict the range
+// of volumes returned. The only supported filter is `dangling`, which
+// selects the volumes that are not referenced by any container.
+func (daemon *Daemon) Volumes(filter string) ([]*types.Volume, error) {
+	volFilters, err := filters.FromParam(filter)
+	if err != nil {
+		return nil, err
+	}
+	for name := range volFilters {
+		if _, ok := acceptedVolumeFilterTags[name]; !ok {
+			return nil, fmt.Errorf("Invalid filter '%s'", name)
+		}
+	}
+
+	filterUsed := false
+	dangling := false
+	if i, ok := volFilters["dangling"]; ok {
+		if len(i) > 1 {
+			return nil, fmt.Errorf("Conflict: cannot use more than 1 value for `dangling` filter")
+		}
+
+		filterValue := i[0]
+		if strings.ToLower(filterValue) == "true" || filterValue == "1" {
+			dangling = true
+		}
+		filterUsed = true
+	}
+
+	var volumesOut []*types.Volume
+	for _, v := range daemon.volumes.List() {
+		if filterUsed && dangling != (daemon.volumes.Count(v) == 0) {
+			continue
+		}
+		volumesOut = append(volumesOut, volumeToAPIType(v))
+	}
+	return volumesOut, nil
+}
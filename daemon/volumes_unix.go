@@ -312,7 +312,7 @@ func (daemon *Daemon) registerMountPoints(container *Container, hostConfig *runc
 			}
 
 			if len(cp.Source) == 0 {
-				v, err := createVolume(cp.Name, cp.Driver)
+				v, err := daemon.createVolume(cp.Name, cp.Driver)
 				if err != nil {
 					return err
 				}
@@ -337,7 +337,7 @@ func (daemon *Daemon) registerMountPoints(container *Container, hostConfig *runc
 
 		if len(bind.Name) > 0 && len(bind.Driver) > 0 {
 			// create the volume
-			v, err := createVolume(bind.Name, bind.Driver)
+			v, err := daemon.createVolume(bind.Name, bind.Driver)
 			if err != nil {
 				return err
 			}
@@ -375,30 +375,26 @@ func (daemon *Daemon) registerMountPoints(container *Container, hostConfig *runc
 	return nil
 }
 
-// createVolume creates a volume.
-func createVolume(name, driverName string) (volume.Volume, error) {
-	vd, err := getVolumeDriver(driverName)
+// createVolume creates a volume and takes a reference to it on behalf
+// of the container that is going to use it.
+func (daemon *Daemon) createVolume(name, driverName string) (volume.Volume, error) {
+	_, err := daemon.volumes.Get(name)
+	exists := err == nil
+
+	v, err := daemon.volumes.Create(name, driverName)
 	if err != nil {
 		return nil, err
 	}
-	return vd.Create(name)
-}
-
-// removeVolume removes a volume.
-func removeVolume(v volume.Volume) error {
-	vd, err := getVolumeDriver(v.DriverName())
-	if err != nil {
-		return nil
+	daemon.volumes.Increment(v)
+	if !exists {
+		daemon.LogVolumeEvent(v.Name(), "create", map[string]string{"driver": v.DriverName()})
 	}
-	return vd.Remove(v)
+	return v, nil
 }
 
 // getVolumeDriver returns the volume driver for the supplied name.
 func getVolumeDriver(name string) (volume.Driver, error) {
-	if name == "" {
-		name = volume.DefaultDriverName
-	}
-	return volumedrivers.Lookup(name)
+	return volumedrivers.GetDriver(name)
 }
 
 // parseVolumeSource parses the origin sources that's mounted into the container.
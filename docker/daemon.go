@@ -18,7 +18,7 @@ import (
 	"github.com/docker/docker/cli"
 	"github.com/docker/docker/cliconfig"
 	"github.com/docker/docker/daemon"
-	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/localcache"
 	"github.com/docker/docker/opts"
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/pkg/pidfile"
@@ -172,7 +172,12 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 	}
 
 	daemonFlags.ParseFlags(args, true)
+	if err := cli.loadConfigFile(); err != nil {
+		fmt.Fprintf(os.Stderr, "docker: %v\n", err)
+		os.Exit(1)
+	}
 	commonFlags.PostParse()
+	cli.Debug = commonFlags.Debug
 
 	if len(commonFlags.Hosts) == 0 {
 		commonFlags.Hosts = []string{opts.DefaultHost}
@@ -192,7 +197,7 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 	}
 
 	if len(cli.LogConfig.Config) > 0 {
-		if err := logger.ValidateLogOpts(cli.LogConfig.Type, cli.LogConfig.Config); err != nil {
+		if err := localcache.ValidateLogOpts(cli.LogConfig.Type, cli.LogConfig.Config); err != nil {
 			logrus.Fatalf("Failed to set log opts: %v", err)
 		}
 	}
@@ -273,6 +278,12 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 		"graphdriver": d.GraphDriver().String(),
 	}).Info("Docker daemon")
 
+	setupConfigReloadTrap(func() {
+		if err := cli.reloadConfigFile(d); err != nil {
+			logrus.Errorf("Error reloading the daemon configuration: %v", err)
+		}
+	})
+
 	signal.Trap(func() {
 		api.Close()
 		<-serveAPIWait
@@ -303,6 +314,64 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 	return nil
 }
 
+// configFlags returns the flag sets holding the flags that the daemon
+// configuration file can set, and the ones parsed from the command line.
+func configFlags() (flags, commandLine []*flag.FlagSet) {
+	flags = []*flag.FlagSet{daemonFlags, commonFlags.FlagSet}
+	commandLine = []*flag.FlagSet{daemonFlags, commonFlags.FlagSet, flag.CommandLine}
+	return flags, commandLine
+}
+
+// loadConfigFile sets the flags that are not given on the command line from
+// the daemon configuration file. A missing file is only an error when it was
+// given with --config-file.
+func (cli *DaemonCli) loadConfigFile() error {
+	flags, commandLine := configFlags()
+	values, err := daemon.ReadConfigFile(cli.ConfigFile)
+	if err != nil {
+		if os.IsNotExist(err) && !daemon.IsSetOnCommandLine("config-file", commandLine) {
+			return nil
+		}
+		return err
+	}
+	return daemon.SetConfigFlags(values, flags, commandLine)
+}
+
+// reloadConfigFile applies the reloadable options of the daemon configuration
+// file to the running daemon d. The options given on the command line keep
+// their values, the other ones missing from the file are reset.
+func (cli *DaemonCli) reloadConfigFile(d *daemon.Daemon) error {
+	_, commandLine := configFlags()
+	values, err := daemon.ReadConfigFile(cli.ConfigFile)
+	if err != nil && (!os.IsNotExist(err) || daemon.IsSetOnCommandLine("config-file", commandLine)) {
+		return err
+	}
+
+	config := new(daemon.Config)
+	registryOptions := new(registry.Options)
+	flags := flag.NewFlagSet("reload", flag.ContinueOnError)
+	config.InstallFlags(flags, absentFromHelp)
+	registryOptions.InstallFlags(flags, absentFromHelp)
+	flags.BoolVar(&config.Debug, []string{"D", "-debug"}, false, "")
+
+	var (
+		options  []string
+		reloaded = make(map[string][]string)
+	)
+	for _, key := range daemon.ReloadableOptions {
+		if v, ok := values[key]; ok {
+			reloaded[key] = v
+			options = append(options, key)
+		} else if !daemon.IsSetOnCommandLine(key, commandLine) {
+			options = append(options, key)
+		}
+	}
+	if err := daemon.SetConfigFlags(reloaded, []*flag.FlagSet{flags}, commandLine); err != nil {
+		return err
+	}
+	return d.Reload(config, registryOptions, options)
+}
+
 // shutdownDaemon just wraps daemon.Shutdown() to handle a timeout in case
 // d.Shutdown() is waiting too long to kill container or worst it's
 // blocked there
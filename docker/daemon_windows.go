@@ -27,3 +27,8 @@ func setDefaultUmask() error {
 func getDaemonConfDir() string {
 	return os.Getenv("PROGRAMDATA") + `\docker\config`
 }
+
+// setupConfigReloadTrap doesn't do anything on windows, the configuration
+// file is only read on start.
+func setupConfigReloadTrap(reload func()) {
+}
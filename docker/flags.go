@@ -23,6 +23,7 @@ func (a byName) Less(i, j int) bool { return a[i].name < a[j].name }
 var dockerCommands = []command{
 	{"attach", "Attach to a running container"},
 	{"build", "Build an image from a Dockerfile"},
+	{"checkpoint", "Checkpoint one or more running containers"},
 	{"commit", "Create a new image from a container's changes"},
 	{"cp", "Copy files/folders from a container to a HOSTDIR or to STDOUT"},
 	{"create", "Create a new container"},
@@ -47,6 +48,7 @@ var dockerCommands = []command{
 	{"push", "Push an image or a repository to a registry"},
 	{"rename", "Rename a container"},
 	{"restart", "Restart a running container"},
+	{"restore", "Restore one or more checkpointed containers"},
 	{"rm", "Remove one or more containers"},
 	{"rmi", "Remove one or more images"},
 	{"run", "Run a command in a new container"},
@@ -58,6 +60,8 @@ var dockerCommands = []command{
 	{"tag", "Tag an image into a repository"},
 	{"top", "Display the running processes of a container"},
 	{"unpause", "Unpause all processes within a container"},
+	{"update", "Update resources of one or more containers"},
 	{"version", "Show the Docker version information"},
+	{"volume", "Manage Docker volumes"},
 	{"wait", "Block until a container stops, then print its exit code"},
 }
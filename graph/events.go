@@ -0,0 +1,26 @@
+package graph
+
+import (
+	"github.com/docker/docker/api/types/events"
+)
+
+// LogImageEvent generates an event related to an image. The id is the
+// image ID, or the reference the event was triggered with, and refName
+// is the name the image is known by, if any. The labels of the image are
+// added to the attributes when the image can still be found.
+func (store *TagStore) LogImageEvent(id, refName, action string) {
+	attributes := map[string]string{}
+	if img, err := store.LookupImage(id); err == nil && img != nil && img.Config != nil {
+		for k, v := range img.Config.Labels {
+			attributes[k] = v
+		}
+	}
+	if refName != "" {
+		attributes["name"] = refName
+	}
+	actor := events.Actor{
+		ID:         id,
+		Attributes: attributes,
+	}
+	store.eventsService.Log(action, events.ImageEventType, actor)
+}
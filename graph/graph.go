@@ -11,7 +11,6 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -21,6 +20,7 @@ import (
 	"github.com/docker/docker/autogen/dockerversion"
 	"github.com/docker/docker/daemon/graphdriver"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/progressreader"
 	"github.com/docker/docker/pkg/streamformatter"
@@ -28,8 +28,6 @@ import (
 	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/pkg/truncindex"
 	"github.com/docker/docker/runconfig"
-	"github.com/vbatts/tar-split/tar/asm"
-	"github.com/vbatts/tar-split/tar/storage"
 )
 
 // The type is used to protect pulling or building related image
@@ -76,20 +74,37 @@ func (r *retainedLayers) Exists(layerID string) bool {
 }
 
 // A Graph is a store for versioned filesystem images and the relationship between them.
+//
+// Images are content-addressable: the ID of an image is the SHA-256 of its
+// configuration, which lists the diff IDs of its layers. The layers are
+// kept in a layer.Store and shared by the images made of them. Images
+// registered with an ID of their own, such as the ones pulled from a v1
+// registry, can also be looked up by that v1 ID.
 type Graph struct {
 	root       string
 	idIndex    *truncindex.TruncIndex
+	v1Index    *truncindex.TruncIndex
 	driver     graphdriver.Driver
+	layers     *layer.Store
 	imageMutex imageMutex // protect images in driver.
 	retained   *retainedLayers
+
+	mu          sync.RWMutex            // protects imageLayers and v1IDs
+	imageLayers map[string]*layer.Layer // layer of each image by ID
+	v1IDs       map[string]string       // image IDs by v1 ID
 }
 
-// file names for ./graph/<ID>/
+// directories under the root of the graph
+const (
+	layersDirName   = "layerdb"
+	contentDirName  = "imagedb/content"
+	metadataDirName = "imagedb/metadata"
+)
+
+// file names for ./imagedb/metadata/<algorithm>/<hex>/
 const (
-	jsonFileName      = "json"
-	layersizeFileName = "layersize"
-	digestFileName    = "checksum"
-	tarDataFileName   = "tar-data.json.gz"
+	v1IDsFileName  = "v1-ids"
+	digestFileName = "checksum"
 )
 
 var (
@@ -107,15 +122,23 @@ func NewGraph(root string, driver graphdriver.Driver) (*Graph, error) {
 		return nil, err
 	}
 	// Create the root directory if it doesn't exists
-	if err := system.MkdirAll(root, 0700); err != nil {
+	for _, dir := range []string{contentDirName, metadataDirName} {
+		if err := system.MkdirAll(filepath.Join(abspath, dir, string(digest.Canonical)), 0700); err != nil {
+			return nil, err
+		}
+	}
+	layers, err := layer.NewStore(filepath.Join(abspath, layersDirName), driver)
+	if err != nil {
 		return nil, err
 	}
 
 	graph := &Graph{
-		root:     abspath,
-		idIndex:  truncindex.NewTruncIndex([]string{}),
-		driver:   driver,
-		retained: &retainedLayers{layerHolders: make(map[string]map[string]struct{})},
+		root:        abspath,
+		driver:      driver,
+		layers:      layers,
+		retained:    &retainedLayers{layerHolders: make(map[string]map[string]struct{})},
+		imageLayers: make(map[string]*layer.Layer),
+		v1IDs:       make(map[string]string),
 	}
 	if err := graph.restore(); err != nil {
 		return nil, err
@@ -123,31 +146,62 @@ func NewGraph(root string, driver graphdriver.Driver) (*Graph, error) {
 	return graph, nil
 }
 
-// IsHeld returns whether the given layerID is being used by an ongoing pull or build.
-func (graph *Graph) IsHeld(layerID string) bool {
-	return graph.retained.Exists(layerID)
+// IsHeld returns whether the given image is being used by an ongoing pull
+// or build, under its ID or one of its v1 IDs.
+func (graph *Graph) IsHeld(id string) bool {
+	if graph.retained.Exists(id) {
+		return true
+	}
+	graph.mu.RLock()
+	defer graph.mu.RUnlock()
+	for v1ID, imgID := range graph.v1IDs {
+		if imgID == id && graph.retained.Exists(v1ID) {
+			return true
+		}
+	}
+	return false
 }
 
 func (graph *Graph) restore() error {
-	dir, err := ioutil.ReadDir(graph.root)
+	dir, err := ioutil.ReadDir(filepath.Join(graph.root, contentDirName, string(digest.Canonical)))
 	if err != nil {
 		return err
 	}
-	var ids = []string{}
+	var ids, v1IDs []string
 	for _, v := range dir {
 		id := v.Name()
-		if graph.driver.Exists(id) {
-			ids = append(ids, id)
+		img, err := graph.readImage(id)
+		if err != nil {
+			logrus.Errorf("Failed to load image %s: %v", id, err)
+			continue
 		}
-	}
+		l, err := graph.layers.Acquire(chainIDOf(img))
+		if err != nil {
+			logrus.Errorf("Failed to get the layers of image %s: %v", id, err)
+			continue
+		}
+		graph.imageLayers[id] = l
+		ids = append(ids, id)
 
-	baseIds, err := graph.restoreBaseImages()
-	if err != nil {
-		return err
+		buf, err := ioutil.ReadFile(filepath.Join(graph.metadataRoot(id), v1IDsFileName))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for _, v1ID := range strings.Fields(string(buf)) {
+			graph.v1IDs[v1ID] = id
+			v1IDs = append(v1IDs, v1ID)
+		}
+	}
+	// Drop the layers no image uses anymore, unless a migration was
+	// interrupted: its layers are the ones of the legacy graph.
+	if _, err := os.Stat(filepath.Join(graph.root, migratedFileName)); err == nil {
+		if err := graph.layers.Prune(); err != nil {
+			return err
+		}
 	}
-	ids = append(ids, baseIds...)
 
 	graph.idIndex = truncindex.NewTruncIndex(ids)
+	graph.v1Index = truncindex.NewTruncIndex(v1IDs)
 	logrus.Debugf("Restored %d elements", len(ids))
 	return nil
 }
@@ -169,38 +223,65 @@ func (graph *Graph) Exists(id string) bool {
 	return true
 }
 
-// Get returns the image with the given id, or an error if the image doesn't exist.
+// Get returns the image with the given id or v1 ID, or an error if the
+// image doesn't exist.
 func (graph *Graph) Get(name string) (*image.Image, error) {
-	id, err := graph.idIndex.Get(name)
+	id, err := graph.lookupID(name)
 	if err != nil {
 		return nil, fmt.Errorf("could not find image: %v", err)
 	}
-	img, err := graph.loadImage(id)
+	l := graph.imageLayer(id)
+	if l == nil {
+		return nil, fmt.Errorf("could not find image: no such id: %s", name)
+	}
+	img, err := graph.readImage(id)
 	if err != nil {
 		return nil, err
 	}
-	if img.ID != id {
-		return nil, fmt.Errorf("Image stored at '%s' has wrong id '%s'", id, img.ID)
-	}
+	img.Size = l.Size()
+	return img, nil
+}
 
-	if img.Size < 0 {
-		size, err := graph.driver.DiffSize(img.ID, img.Parent)
-		if err != nil {
-			return nil, fmt.Errorf("unable to calculate size of image id %q: %s", img.ID, err)
+// lookupID returns the ID of the image named by an ID, a v1 ID or a prefix
+// of one of them.
+func (graph *Graph) lookupID(name string) (string, error) {
+	id, err := graph.idIndex.Get(name)
+	if err == nil {
+		return id, nil
+	}
+	if v1ID, v1Err := graph.v1Index.Get(name); v1Err == nil {
+		graph.mu.RLock()
+		defer graph.mu.RUnlock()
+		if id, ok := graph.v1IDs[v1ID]; ok {
+			return id, nil
 		}
+	}
+	return "", err
+}
 
-		img.Size = size
-		if err := graph.saveSize(graph.imageRoot(id), img.Size); err != nil {
-			return nil, err
-		}
+func (graph *Graph) imageLayer(id string) *layer.Layer {
+	graph.mu.RLock()
+	defer graph.mu.RUnlock()
+	return graph.imageLayers[id]
+}
+
+// CacheID returns the ID under which the storage driver holds the layer of
+// the image name.
+func (graph *Graph) CacheID(name string) (string, error) {
+	id, err := graph.lookupID(name)
+	if err != nil {
+		return "", fmt.Errorf("could not find image: %v", err)
 	}
-	return img, nil
+	l := graph.imageLayer(id)
+	if l == nil {
+		return "", fmt.Errorf("could not find image: no such id: %s", name)
+	}
+	return l.CacheID(), nil
 }
 
 // Create creates a new image and registers it in the graph.
 func (graph *Graph) Create(layerData archive.ArchiveReader, containerID, containerImage, comment, author string, containerConfig, config *runconfig.Config) (*image.Image, error) {
 	img := &image.Image{
-		ID:            stringid.GenerateRandomID(),
 		Comment:       comment,
 		Created:       time.Now().UTC(),
 		DockerVersion: dockerversion.VERSION,
@@ -216,15 +297,16 @@ func (graph *Graph) Create(layerData archive.ArchiveReader, containerID, contain
 		img.ContainerConfig = *containerConfig
 	}
 
-	if err := graph.Register(img, layerData); err != nil {
+	id, err := graph.register(img, layerData)
+	if err != nil {
 		return nil, err
 	}
-	return img, nil
+	return graph.Get(id)
 }
 
-// Register imports a pre-existing image into the graph.
-func (graph *Graph) Register(img *image.Image, layerData archive.ArchiveReader) (err error) {
-
+// Register imports a pre-existing image into the graph. The ID of img is
+// kept as the v1 ID of the image, whose ID is computed from its content.
+func (graph *Graph) Register(img *image.Image, layerData archive.ArchiveReader) error {
 	if err := image.ValidateID(img.ID); err != nil {
 		return err
 	}
@@ -234,55 +316,125 @@ func (graph *Graph) Register(img *image.Image, layerData archive.ArchiveReader)
 	graph.imageMutex.Lock(img.ID)
 	defer graph.imageMutex.Unlock(img.ID)
 
-	// The returned `error` must be named in this function's signature so that
-	// `err` is not shadowed in this deferred cleanup.
-	defer func() {
-		// If any error occurs, remove the new dir from the driver.
-		// Don't check for errors since the dir might not have been created.
-		if err != nil {
-			graph.driver.Remove(img.ID)
-		}
-	}()
-
-	// (This is a convenience to save time. Race conditions are taken care of by os.Rename)
 	if graph.Exists(img.ID) {
 		return fmt.Errorf("Image %s already exists", img.ID)
 	}
 
-	// Ensure that the image root does not exist on the filesystem
-	// when it is not registered in the graph.
-	// This is common when you switch from one graph driver to another
-	if err := os.RemoveAll(graph.imageRoot(img.ID)); err != nil && !os.IsNotExist(err) {
+	id, err := graph.register(img, layerData)
+	if err != nil {
 		return err
 	}
+	if id == img.ID {
+		return nil
+	}
+	return graph.addV1ID(id, img.ID)
+}
 
-	// If the driver has this ID but the graph doesn't, remove it from the driver to start fresh.
-	// (the graph is the source of truth).
-	// Ignore errors, since we don't know if the driver correctly returns ErrNotExist.
-	// (FIXME: make that mandatory for drivers).
-	graph.driver.Remove(img.ID)
+// register stores the layer and the configuration of img, whose parent is
+// given by ID or v1 ID, and returns the ID of the image.
+func (graph *Graph) register(img *image.Image, layerData archive.ArchiveReader) (string, error) {
+	config := *img
+	var parentLayer *layer.Layer
+	if img.Parent != "" {
+		parent, err := graph.Get(img.Parent)
+		if err != nil {
+			return "", err
+		}
+		config.Parent = parent.ID
+		parentLayer = graph.imageLayer(parent.ID)
+	}
 
-	tmp, err := graph.mktemp("")
-	defer os.RemoveAll(tmp)
+	l, err := graph.createLayer(img, parentLayer, layerData)
 	if err != nil {
-		return fmt.Errorf("mktemp failed: %s", err)
+		return "", err
 	}
+	config.RootFS = &image.RootFS{Type: "layers", DiffIDs: l.DiffIDs()}
+	if img.RootFS != nil && !equalDiffIDs(img.RootFS.DiffIDs, config.RootFS.DiffIDs) {
+		graph.layers.Release(l)
+		return "", fmt.Errorf("The layers of image %s do not match its configuration", img.ID)
+	}
+	return graph.storeImage(&config, l)
+}
 
-	// Create root filesystem in the driver
-	if err := createRootFilesystemInDriver(graph, img, layerData); err != nil {
-		return err
+// storeImage saves the configuration of an image made of the layer l,
+// taking over the reference to l, and returns the ID of the image.
+func (graph *Graph) storeImage(img *image.Image, l *layer.Layer) (string, error) {
+	config, err := img.RawConfig()
+	if err != nil {
+		graph.layers.Release(l)
+		return "", err
 	}
+	id := image.ComputeID(config)
 
-	// Apply the diff/layer
-	if err := graph.storeImage(img, layerData, tmp); err != nil {
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	if _, exists := graph.imageLayers[id]; exists {
+		logrus.Debugf("Image %s already exists", id)
+		graph.layers.Release(l)
+		return id, nil
+	}
+
+	tmp, err := graph.newTempFile()
+	if err != nil {
+		graph.layers.Release(l)
+		return "", err
+	}
+	defer os.RemoveAll(filepath.Dir(tmp.Name()))
+	_, err = tmp.Write(config)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = system.MkdirAll(graph.metadataRoot(id), 0700)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), graph.contentPath(id))
+	}
+	if err != nil {
+		graph.layers.Release(l)
+		return "", fmt.Errorf("Error storing image %s: %v", id, err)
+	}
+
+	graph.imageLayers[id] = l
+	graph.idIndex.Add(id)
+	return id, nil
+}
+
+// addV1ID records v1ID as another name of the image id.
+func (graph *Graph) addV1ID(id, v1ID string) error {
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	f, err := os.OpenFile(filepath.Join(graph.metadataRoot(id), v1IDsFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
 		return err
 	}
-	// Commit
-	if err := os.Rename(tmp, graph.imageRoot(img.ID)); err != nil {
+	defer f.Close()
+	if _, err := fmt.Fprintln(f, v1ID); err != nil {
 		return err
 	}
-	graph.idIndex.Add(img.ID)
-	return nil
+	graph.v1IDs[v1ID] = id
+	return graph.v1Index.Add(v1ID)
+}
+
+// readImage reads the configuration of the image id and checks that it
+// matches the ID.
+func (graph *Graph) readImage(id string) (*image.Image, error) {
+	if err := image.ValidateID(id); err != nil {
+		return nil, err
+	}
+	config, err := ioutil.ReadFile(graph.contentPath(id))
+	if err != nil {
+		return nil, err
+	}
+	if computed := image.ComputeID(config); computed != id {
+		return nil, fmt.Errorf("Image %s has wrong content, with ID %s", id, computed)
+	}
+	img, err := image.NewImgJSON(config)
+	if err != nil {
+		return nil, err
+	}
+	img.ID = id
+	return img, nil
 }
 
 // TempLayerArchive creates a temporary archive of the given image's filesystem layer.
@@ -351,28 +503,35 @@ func bufferToFile(f *os.File, src io.Reader) (int64, digest.Digest, error) {
 	return n, digest.NewDigest("sha256", h), nil
 }
 
-// Delete atomically removes an image from the graph.
+// Delete atomically removes an image from the graph and releases its
+// layer.
 func (graph *Graph) Delete(name string) error {
-	id, err := graph.idIndex.Get(name)
+	id, err := graph.lookupID(name)
 	if err != nil {
 		return err
 	}
-	tmp, err := graph.mktemp("")
-	graph.idIndex.Delete(id)
-	if err == nil {
-		if err := os.Rename(graph.imageRoot(id), tmp); err != nil {
-			// On err make tmp point to old dir and cleanup unused tmp dir
-			os.RemoveAll(tmp)
-			tmp = graph.imageRoot(id)
+	graph.mu.Lock()
+	l := graph.imageLayers[id]
+	delete(graph.imageLayers, id)
+	for v1ID, imgID := range graph.v1IDs {
+		if imgID == id {
+			delete(graph.v1IDs, v1ID)
+			graph.v1Index.Delete(v1ID)
 		}
-	} else {
-		// On err make tmp point to old dir for cleanup
-		tmp = graph.imageRoot(id)
 	}
-	// Remove rootfs data from the driver
-	graph.driver.Remove(id)
-	// Remove the trashed image directory
-	return os.RemoveAll(tmp)
+	graph.idIndex.Delete(id)
+	graph.mu.Unlock()
+
+	if err := os.Remove(graph.contentPath(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.RemoveAll(graph.metadataRoot(id)); err != nil {
+		return err
+	}
+	if l == nil {
+		return nil
+	}
+	return graph.layers.Release(l)
 }
 
 // Map returns a list of all images in the graph, addressable by ID.
@@ -442,64 +601,21 @@ func (graph *Graph) Heads() map[string]*image.Image {
 	return heads
 }
 
-func (graph *Graph) imageRoot(id string) string {
-	return filepath.Join(graph.root, id)
+func (graph *Graph) contentPath(id string) string {
+	return filepath.Join(graph.root, contentDirName, string(digest.Canonical), id)
 }
 
-// loadImage fetches the image with the given id from the graph.
-func (graph *Graph) loadImage(id string) (*image.Image, error) {
-	root := graph.imageRoot(id)
-
-	// Open the JSON file to decode by streaming
-	jsonSource, err := os.Open(jsonPath(root))
-	if err != nil {
-		return nil, err
-	}
-	defer jsonSource.Close()
-
-	img := &image.Image{}
-	dec := json.NewDecoder(jsonSource)
-
-	// Decode the JSON data
-	if err := dec.Decode(img); err != nil {
-		return nil, err
-	}
-	if err := image.ValidateID(img.ID); err != nil {
-		return nil, err
-	}
-
-	if buf, err := ioutil.ReadFile(filepath.Join(root, layersizeFileName)); err != nil {
-		if !os.IsNotExist(err) {
-			return nil, err
-		}
-		// If the layersize file does not exist then set the size to a negative number
-		// because a layer size of 0 (zero) is valid
-		img.Size = -1
-	} else {
-		// Using Atoi here instead would temporarily convert the size to a machine
-		// dependent integer type, which causes images larger than 2^31 bytes to
-		// display negative sizes on 32-bit machines:
-		size, err := strconv.ParseInt(string(buf), 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		img.Size = int64(size)
-	}
-
-	return img, nil
-}
-
-// saveSize stores the `size` in the provided graph `img` directory `root`.
-func (graph *Graph) saveSize(root string, size int64) error {
-	if err := ioutil.WriteFile(filepath.Join(root, layersizeFileName), []byte(strconv.FormatInt(size, 10)), 0600); err != nil {
-		return fmt.Errorf("Error storing image size in %s/%s: %s", root, layersizeFileName, err)
-	}
-	return nil
+func (graph *Graph) metadataRoot(id string) string {
+	return filepath.Join(graph.root, metadataDirName, string(digest.Canonical), id)
 }
 
 // SetDigest sets the digest for the image layer to the provided value.
 func (graph *Graph) SetDigest(id string, dgst digest.Digest) error {
-	root := graph.imageRoot(id)
+	imgID, err := graph.lookupID(id)
+	if err != nil {
+		return err
+	}
+	root := graph.metadataRoot(imgID)
 	if err := ioutil.WriteFile(filepath.Join(root, digestFileName), []byte(dgst.String()), 0600); err != nil {
 		return fmt.Errorf("Error storing digest in %s/%s: %s", root, digestFileName, err)
 	}
@@ -508,7 +624,11 @@ func (graph *Graph) SetDigest(id string, dgst digest.Digest) error {
 
 // GetDigest gets the digest for the provide image layer id.
 func (graph *Graph) GetDigest(id string) (digest.Digest, error) {
-	root := graph.imageRoot(id)
+	imgID, err := graph.lookupID(id)
+	if err != nil {
+		return "", err
+	}
+	root := graph.metadataRoot(imgID)
 	cs, err := ioutil.ReadFile(filepath.Join(root, digestFileName))
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -521,93 +641,46 @@ func (graph *Graph) GetDigest(id string) (digest.Digest, error) {
 
 // RawJSON returns the JSON representation for an image as a byte array.
 func (graph *Graph) RawJSON(id string) ([]byte, error) {
-	root := graph.imageRoot(id)
-
-	buf, err := ioutil.ReadFile(jsonPath(root))
+	img, err := graph.Get(id)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to read json for image %s: %s", id, err)
 	}
-
-	return buf, nil
-}
-
-func jsonPath(root string) string {
-	return filepath.Join(root, jsonFileName)
+	return json.Marshal(img)
 }
 
-func (graph *Graph) disassembleAndApplyTarLayer(img *image.Image, layerData archive.ArchiveReader, root string) error {
-	// this is saving the tar-split metadata
-	mf, err := os.OpenFile(filepath.Join(root, tarDataFileName), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(0600))
-	if err != nil {
-		return err
-	}
-	mfz := gzip.NewWriter(mf)
-	metaPacker := storage.NewJSONPacker(mfz)
-	defer mf.Close()
-	defer mfz.Close()
-
-	inflatedLayerData, err := archive.DecompressStream(layerData)
-	if err != nil {
-		return err
+// TarLayer returns a tar archive of the image's filesystem layer.
+func (graph *Graph) TarLayer(img *image.Image) (archive.Archive, error) {
+	l := graph.imageLayer(img.ID)
+	if l == nil {
+		return nil, fmt.Errorf("could not find image: no such id: %s", img.ID)
 	}
+	return l.TarStream()
+}
 
-	// we're passing nil here for the file putter, because the ApplyDiff will
-	// handle the extraction of the archive
-	rdr, err := asm.NewInputTarStream(inflatedLayerData, metaPacker, nil)
-	if err != nil {
-		return err
+// chainIDOf returns the ID of the top layer of img.
+func chainIDOf(img *image.Image) layer.ChainID {
+	if img.RootFS == nil {
+		return ""
 	}
+	return layer.CreateChainIDFromDiffIDs(img.RootFS.DiffIDs)
+}
 
-	if img.Size, err = graph.driver.ApplyDiff(img.ID, img.Parent, archive.ArchiveReader(rdr)); err != nil {
-		return err
+// layerChainID returns the chain ID of l, which may be nil.
+func layerChainID(l *layer.Layer) layer.ChainID {
+	if l == nil {
+		return ""
 	}
-
-	return nil
+	return l.ChainID()
 }
 
-func (graph *Graph) assembleTarLayer(img *image.Image) (archive.Archive, error) {
-	root := graph.imageRoot(img.ID)
-	mFileName := filepath.Join(root, tarDataFileName)
-	mf, err := os.Open(mFileName)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			logrus.Errorf("failed to open %q: %s", mFileName, err)
-		}
-		return nil, err
+func equalDiffIDs(a, b []layer.DiffID) bool {
+	if len(a) != len(b) {
+		return false
 	}
-	pR, pW := io.Pipe()
-	// this will need to be in a goroutine, as we are returning the stream of a
-	// tar archive, but can not close the metadata reader early (when this
-	// function returns)...
-	go func() {
-		defer mf.Close()
-		// let's reassemble!
-		logrus.Debugf("[graph] TarLayer with reassembly: %s", img.ID)
-		mfz, err := gzip.NewReader(mf)
-		if err != nil {
-			pW.CloseWithError(fmt.Errorf("[graph] error with %s:  %s", mFileName, err))
-			return
-		}
-		defer mfz.Close()
-
-		// get our relative path to the container
-		fsLayer, err := graph.driver.Get(img.ID, "")
-		if err != nil {
-			pW.CloseWithError(err)
-			return
-		}
-		defer graph.driver.Put(img.ID)
-
-		metaUnpacker := storage.NewJSONUnpacker(mfz)
-		fileGetter := storage.NewPathFileGetter(fsLayer)
-		logrus.Debugf("[graph] %s is at %q", img.ID, fsLayer)
-		ots := asm.NewOutputTarStream(fileGetter, metaUnpacker)
-		defer ots.Close()
-		if _, err := io.Copy(pW, ots); err != nil {
-			pW.CloseWithError(err)
-			return
+	for i := range a {
+		if a[i] != b[i] {
+			return false
 		}
-		pW.Close()
-	}()
-	return pR, nil
+	}
+	return true
 }
@@ -42,7 +42,11 @@ func TestMount(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if _, err := driver.Get(image.ID, ""); err != nil {
+	cacheID, err := graph.CacheID(image.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := driver.Get(cacheID, ""); err != nil {
 		t.Fatal(err)
 	}
 
@@ -141,8 +145,8 @@ func TestRegister(t *testing.T) {
 	if resultImg, err := graph.Get(image.ID); err != nil {
 		t.Fatal(err)
 	} else {
-		if resultImg.ID != image.ID {
-			t.Fatalf("Wrong image ID. Should be '%s', not '%s'", image.ID, resultImg.ID)
+		if images[resultImg.ID] == nil {
+			t.Fatalf("Wrong image ID. Should be the one of the registered image, not '%s'", resultImg.ID)
 		}
 		if resultImg.Comment != image.Comment {
 			t.Fatalf("Wrong image comment. Should be '%s', not '%s'", image.Comment, resultImg.Comment)
@@ -250,8 +254,12 @@ func TestByParent(t *testing.T) {
 	_ = graph.Register(childImage1, archive2)
 	_ = graph.Register(childImage2, archive3)
 
+	parent, err := graph.Get(parentImage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
 	byParent := graph.ByParent()
-	numChildren := len(byParent[parentImage.ID])
+	numChildren := len(byParent[parent.ID])
 	if numChildren != 2 {
 		t.Fatalf("Expected 2 children, found %d", numChildren)
 	}
@@ -281,7 +289,7 @@ func tempGraph(t *testing.T) (*Graph, graphdriver.Driver) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	driver, err := graphdriver.New(tmp, nil)
+	driver, err := graphdriver.New(tmp, nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
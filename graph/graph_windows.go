@@ -3,14 +3,14 @@
 package graph
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/daemon/graphdriver/windows"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/stringid"
 )
 
 // SetupInitLayer populates a directory with mountpoints suitable
@@ -19,146 +19,49 @@ func SetupInitLayer(initLayer string) error {
 	return nil
 }
 
-func createRootFilesystemInDriver(graph *Graph, img *image.Image, layerData archive.ArchiveReader) error {
-	if wd, ok := graph.driver.(*windows.WindowsGraphDriver); ok {
-		if img.Container != "" && layerData == nil {
-			logrus.Debugf("Copying from container %s.", img.Container)
+// createLayer registers the layer of img on top of parent from layerData.
+// An image committed from a container without layerData gets a copy of the
+// changes of the container instead.
+func (graph *Graph) createLayer(img *image.Image, parent *layer.Layer, layerData archive.ArchiveReader) (*layer.Layer, error) {
+	if wd, ok := graph.driver.(*windows.WindowsGraphDriver); ok && img.Container != "" && layerData == nil {
+		logrus.Debugf("Copying from container %s.", img.Container)
 
-			var ids []string
-			if img.Parent != "" {
-				parentImg, err := graph.Get(img.Parent)
-				if err != nil {
-					return err
-				}
-
-				ids, err = graph.ParentLayerIds(parentImg)
-				if err != nil {
-					return err
-				}
-			}
-
-			if err := wd.CopyDiff(img.Container, img.ID, wd.LayerIdsToPaths(ids)); err != nil {
-				return fmt.Errorf("Driver %s failed to copy image rootfs %s: %s", graph.driver, img.Container, err)
-			}
-		} else if img.Parent == "" {
-			if err := graph.driver.Create(img.ID, img.Parent); err != nil {
-				return fmt.Errorf("Driver %s failed to create image rootfs %s: %s", graph.driver, img.ID, err)
-			}
+		cacheID := stringid.GenerateRandomID()
+		if err := wd.CopyDiff(img.Container, cacheID, wd.LayerIdsToPaths(layerCacheIDs(parent))); err != nil {
+			return nil, fmt.Errorf("Driver %s failed to copy image rootfs %s: %s", graph.driver, img.Container, err)
 		}
-	} else {
-		// This fallback allows the use of VFS during daemon development.
-		if err := graph.driver.Create(img.ID, img.Parent); err != nil {
-			return fmt.Errorf("Driver %s failed to create image rootfs %s: %s", graph.driver, img.ID, err)
+		l, err := graph.layers.RegisterExisting(cacheID, layerChainID(parent), nil)
+		if err != nil || l.CacheID() != cacheID {
+			graph.driver.Remove(cacheID)
 		}
-	}
-	return nil
-}
-
-func (graph *Graph) restoreBaseImages() ([]string, error) {
-	// TODO Windows. This needs implementing (@swernli)
-	return nil, nil
-}
-
-// ParentLayerIds returns a list of all parent image IDs for the given image.
-func (graph *Graph) ParentLayerIds(img *image.Image) (ids []string, err error) {
-	for i := img; i != nil && err == nil; i, err = graph.GetParent(i) {
-		ids = append(ids, i.ID)
+		return l, err
 	}
 
-	return
-}
-
-// storeImage stores file system layer data for the given image to the
-// graph's storage driver. Image metadata is stored in a file
-// at the specified root directory.
-func (graph *Graph) storeImage(img *image.Image, layerData archive.ArchiveReader, root string) (err error) {
-
-	if wd, ok := graph.driver.(*windows.WindowsGraphDriver); ok {
-		// Store the layer. If layerData is not nil and this isn't a base image,
-		// unpack it into the new layer
-		if layerData != nil && img.Parent != "" {
-			var ids []string
-			if img.Parent != "" {
-				parentImg, err := graph.Get(img.Parent)
-				if err != nil {
-					return err
-				}
-
-				ids, err = graph.ParentLayerIds(parentImg)
-				if err != nil {
-					return err
-				}
-			}
-
-			if img.Size, err = wd.Import(img.ID, layerData, wd.LayerIdsToPaths(ids)); err != nil {
-				return err
-			}
-		}
-
-		if err := graph.saveSize(root, img.Size); err != nil {
-			return err
+	if layerData == nil {
+		var err error
+		if layerData, err = archive.Generate(); err != nil {
+			return nil, err
 		}
-
-		f, err := os.OpenFile(jsonPath(root), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(0600))
-		if err != nil {
-			return err
-		}
-
-		defer f.Close()
-
-		return json.NewEncoder(f).Encode(img)
-	}
-	// We keep this functionality here so that we can still work with the
-	// VFS driver during development. This will not be used for actual running
-	// of Windows containers. Without this code, it would not be possible to
-	// docker pull using the VFS driver.
-
-	// Store the layer. If layerData is not nil, unpack it into the new layer
-	if layerData != nil {
-		if err := graph.disassembleAndApplyTarLayer(img, layerData, root); err != nil {
-			return err
-		}
-	}
-
-	if err := graph.saveSize(root, img.Size); err != nil {
-		return err
 	}
+	return graph.layers.Register(layerData, layerChainID(parent))
+}
 
-	f, err := os.OpenFile(jsonPath(root), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(0600))
-	if err != nil {
-		return err
+// ParentLayerIds returns the storage driver IDs of the layer of the given
+// image and of all of its parents.
+func (graph *Graph) ParentLayerIds(img *image.Image) ([]string, error) {
+	l := graph.imageLayer(img.ID)
+	if l == nil {
+		return nil, fmt.Errorf("could not find image: no such id: %s", img.ID)
 	}
-
-	defer f.Close()
-
-	return json.NewEncoder(f).Encode(img)
+	return layerCacheIDs(l), nil
 }
 
-// TarLayer returns a tar archive of the image's filesystem layer.
-func (graph *Graph) TarLayer(img *image.Image) (arch archive.Archive, err error) {
-	if wd, ok := graph.driver.(*windows.WindowsGraphDriver); ok {
-		var ids []string
-		if img.Parent != "" {
-			parentImg, err := graph.Get(img.Parent)
-			if err != nil {
-				return nil, err
-			}
-
-			ids, err = graph.ParentLayerIds(parentImg)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		return wd.Export(img.ID, wd.LayerIdsToPaths(ids))
-	}
-	// We keep this functionality here so that we can still work with the VFS
-	// driver during development. VFS is not supported (and just will not work)
-	// for Windows containers.
-	rdr, err := graph.assembleTarLayer(img)
-	if err != nil {
-		logrus.Debugf("[graph] TarLayer with traditional differ: %s", img.ID)
-		return graph.driver.Diff(img.ID, img.Parent)
+// layerCacheIDs returns the storage driver IDs of l and of its parents,
+// from l to the base layer.
+func layerCacheIDs(l *layer.Layer) []string {
+	var ids []string
+	for ; l != nil; l = l.Parent() {
+		ids = append(ids, l.CacheID())
 	}
-	return rdr, nil
+	return ids
 }
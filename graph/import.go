@@ -70,6 +70,6 @@ func (s *TagStore) Import(src string, repo string, tag string, inConfig io.ReadC
 		logID = utils.ImageReference(logID, tag)
 	}
 
-	s.eventsService.Log("import", logID, "")
+	s.LogImageEvent(logID, logID, "import")
 	return nil
 }
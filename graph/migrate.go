@@ -0,0 +1,204 @@
+package graph
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/vbatts/tar-split/tar/asm"
+	"github.com/vbatts/tar-split/tar/storage"
+)
+
+// file names for the legacy ./graph/<ID>/
+const (
+	legacyJSONFileName    = "json"
+	legacyDigestFileName  = "checksum"
+	legacyTarDataFileName = "tar-data.json.gz"
+)
+
+// migratedFileName marks a graph into which the legacy graph directory was
+// migrated.
+const migratedFileName = ".migrated"
+
+// Migrate imports the images of the graph directory used by the previous
+// releases, oldRoot, the first time it is called. The layers stay where
+// they are in the storage driver, the images keep their old ID as v1 ID.
+// The images whose layer or parent is missing are skipped. oldRoot is left
+// in place but is no longer used.
+func (graph *Graph) Migrate(oldRoot string) error {
+	marker := filepath.Join(graph.root, migratedFileName)
+	if _, err := os.Stat(marker); err == nil {
+		return nil
+	}
+
+	dir, err := ioutil.ReadDir(oldRoot)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	images := make(map[string]*image.Image)
+	for _, v := range dir {
+		id := v.Name()
+		if image.ValidateID(id) != nil {
+			continue
+		}
+		if !graph.driver.Exists(id) {
+			logrus.Warnf("Skipping the migration of image %s, whose layer is missing", id)
+			continue
+		}
+		buf, err := ioutil.ReadFile(filepath.Join(oldRoot, id, legacyJSONFileName))
+		if err != nil {
+			return err
+		}
+		img, err := image.NewImgJSON(buf)
+		if err != nil {
+			return fmt.Errorf("Failed to read image %s: %v", id, err)
+		}
+		if img.ID != id {
+			logrus.Warnf("Skipping the migration of image %s, stored with ID %s", id, img.ID)
+			continue
+		}
+		images[id] = img
+	}
+	if len(images) > 0 {
+		logrus.Infof("Migrating %d images from %s", len(images), oldRoot)
+	}
+
+	// migrate imports the image id after its parents and returns its new
+	// ID, or "" when it cannot be imported.
+	migrated := make(map[string]string)
+	var migrate func(id string) (string, error)
+	migrate = func(id string) (string, error) {
+		if newID, ok := migrated[id]; ok {
+			return newID, nil
+		}
+		img, ok := images[id]
+		if !ok {
+			return "", nil
+		}
+		var parent *layer.Layer
+		if img.Parent != "" {
+			parentID, err := migrate(img.Parent)
+			if err != nil {
+				return "", err
+			}
+			if parentID == "" {
+				logrus.Warnf("Skipping the migration of image %s, whose parent %s is missing", id, img.Parent)
+				migrated[id] = ""
+				return "", nil
+			}
+			img.Parent = parentID
+			parent = graph.imageLayer(parentID)
+		}
+		newID, err := graph.migrateImage(oldRoot, img, parent)
+		if err != nil {
+			return "", fmt.Errorf("Failed to migrate image %s: %v", id, err)
+		}
+		logrus.Debugf("Migrated image %s to %s", id, newID)
+		migrated[id] = newID
+		return newID, nil
+	}
+	for id := range images {
+		if _, err := migrate(id); err != nil {
+			return err
+		}
+	}
+
+	if err := graph.layers.Prune(); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(marker, []byte{}, 0600)
+}
+
+// migrateImage imports the legacy image img, whose parent is already
+// translated to its new ID, on top of the layer parent.
+func (graph *Graph) migrateImage(oldRoot string, img *image.Image, parent *layer.Layer) (string, error) {
+	v1ID := img.ID
+
+	// An earlier migration may have been interrupted after this image.
+	graph.mu.RLock()
+	id, done := graph.v1IDs[v1ID]
+	graph.mu.RUnlock()
+	if done {
+		return id, nil
+	}
+
+	ts, err := legacyTarStream(graph, oldRoot, v1ID)
+	if err != nil {
+		return "", err
+	}
+	l, err := graph.layers.RegisterExisting(v1ID, layerChainID(parent), ts)
+	if ts != nil {
+		ts.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	config := *img
+	config.RootFS = &image.RootFS{Type: "layers", DiffIDs: l.DiffIDs()}
+	if id, err = graph.storeImage(&config, l); err != nil {
+		return "", err
+	}
+	if err := graph.addV1ID(id, v1ID); err != nil {
+		return "", err
+	}
+
+	if buf, err := ioutil.ReadFile(filepath.Join(oldRoot, v1ID, legacyDigestFileName)); err == nil {
+		if dgst, err := digest.ParseDigest(strings.TrimSpace(string(buf))); err == nil {
+			if err := graph.SetDigest(id, dgst); err != nil {
+				return "", err
+			}
+		}
+	}
+	return id, nil
+}
+
+// legacyTarStream rebuilds the tar stream of the legacy image id from its
+// tar-split metadata. It returns nil when the image has none.
+func legacyTarStream(graph *Graph, oldRoot, id string) (io.ReadCloser, error) {
+	mFileName := filepath.Join(oldRoot, id, legacyTarDataFileName)
+	mf, err := os.Open(mFileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	pR, pW := io.Pipe()
+	// this will need to be in a goroutine, as we are returning the stream of a
+	// tar archive, but can not close the metadata reader early (when this
+	// function returns)...
+	go func() {
+		defer mf.Close()
+		mfz, err := gzip.NewReader(mf)
+		if err != nil {
+			pW.CloseWithError(fmt.Errorf("[graph] error with %s:  %s", mFileName, err))
+			return
+		}
+		defer mfz.Close()
+
+		fsLayer, err := graph.driver.Get(id, "")
+		if err != nil {
+			pW.CloseWithError(err)
+			return
+		}
+		defer graph.driver.Put(id)
+
+		ots := asm.NewOutputTarStream(storage.NewPathFileGetter(fsLayer), storage.NewJSONUnpacker(mfz))
+		defer ots.Close()
+		if _, err := io.Copy(pW, ots); err != nil {
+			pW.CloseWithError(err)
+			return
+		}
+		pW.Close()
+	}()
+	return pR, nil
+}
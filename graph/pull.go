@@ -131,7 +131,7 @@ func (s *TagStore) Pull(image string, tag string, imagePullConfig *ImagePullConf
 
 		}
 
-		s.eventsService.Log("pull", logName, "")
+		s.LogImageEvent(logName, logName, "pull")
 		return nil
 	}
 
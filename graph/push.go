@@ -114,7 +114,7 @@ func (s *TagStore) Push(localName string, imagePushConfig *ImagePushConfig) erro
 
 		}
 
-		s.eventsService.Log("push", repoInfo.LocalName, "")
+		s.LogImageEvent(repoInfo.LocalName, repoInfo.LocalName, "push")
 		return nil
 	}
 
@@ -106,10 +106,30 @@ func NewTagStore(path string, cfg *TagStoreConfig) (*TagStore, error) {
 		}
 	} else if err != nil {
 		return nil, err
+	} else if err := store.resolveV1IDs(); err != nil {
+		return nil, err
 	}
 	return store, nil
 }
 
+// resolveV1IDs replaces the v1 IDs, which the images had before they were
+// content-addressable, by the IDs of the images.
+func (store *TagStore) resolveV1IDs() error {
+	changed := false
+	for _, repository := range store.Repositories {
+		for ref, id := range repository {
+			if img, err := store.graph.Get(id); err == nil && img.ID != id {
+				repository[ref] = img.ID
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return store.save()
+}
+
 func (store *TagStore) save() error {
 	// Store the json ball
 	jsonData, err := json.Marshal(store)
@@ -13,6 +13,7 @@ import (
 	_ "github.com/docker/docker/daemon/graphdriver/vfs" // import the vfs driver so it is used in the tests
 	"github.com/docker/docker/graph/tags"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/trust"
 	"github.com/docker/docker/utils"
 )
@@ -54,7 +55,7 @@ func fakeTar() (io.Reader, error) {
 }
 
 func mkTestTagStore(root string, t *testing.T) *TagStore {
-	driver, err := graphdriver.New(root, nil)
+	driver, err := graphdriver.New(root, nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -81,7 +82,7 @@ func mkTestTagStore(root string, t *testing.T) *TagStore {
 	if err != nil {
 		t.Fatal(err)
 	}
-	img := &image.Image{ID: testOfficialImageID}
+	img := &image.Image{ID: testOfficialImageID, Comment: "official"}
 	if err := graph.Register(img, officialArchive); err != nil {
 		t.Fatal(err)
 	}
@@ -92,7 +93,7 @@ func mkTestTagStore(root string, t *testing.T) *TagStore {
 	if err != nil {
 		t.Fatal(err)
 	}
-	img = &image.Image{ID: testPrivateImageID}
+	img = &image.Image{ID: testPrivateImageID, Comment: "private"}
 	if err := graph.Register(img, privateArchive); err != nil {
 		t.Fatal(err)
 	}
@@ -114,11 +115,26 @@ func TestLookupImage(t *testing.T) {
 	store := mkTestTagStore(tmp, t)
 	defer store.graph.driver.Cleanup()
 
+	// The images are registered with v1 IDs, their IDs are computed from
+	// their content.
+	officialImg, err := store.graph.Get(testOfficialImageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	officialID := officialImg.ID
+	privateImg, err := store.graph.Get(testPrivateImageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateID := privateImg.ID
+
 	officialLookups := []string{
 		testOfficialImageID,
 		testOfficialImageIDShort,
-		testOfficialImageName + ":" + testOfficialImageID,
-		testOfficialImageName + ":" + testOfficialImageIDShort,
+		officialID,
+		stringid.TruncateID(officialID),
+		testOfficialImageName + ":" + officialID,
+		testOfficialImageName + ":" + stringid.TruncateID(officialID),
 		testOfficialImageName,
 		testOfficialImageName + ":" + tags.DefaultTag,
 		"docker.io/" + testOfficialImageName,
@@ -136,8 +152,10 @@ func TestLookupImage(t *testing.T) {
 	privateLookups := []string{
 		testPrivateImageID,
 		testPrivateImageIDShort,
-		testPrivateImageName + ":" + testPrivateImageID,
-		testPrivateImageName + ":" + testPrivateImageIDShort,
+		privateID,
+		stringid.TruncateID(privateID),
+		testPrivateImageName + ":" + privateID,
+		testPrivateImageName + ":" + stringid.TruncateID(privateID),
 		testPrivateImageName,
 		testPrivateImageName + ":" + tags.DefaultTag,
 	}
@@ -156,8 +174,8 @@ func TestLookupImage(t *testing.T) {
 			t.Errorf("Error looking up %s: %s", name, err)
 		} else if img == nil {
 			t.Errorf("Expected 1 image, none found: %s", name)
-		} else if img.ID != testOfficialImageID {
-			t.Errorf("Expected ID '%s' found '%s'", testOfficialImageID, img.ID)
+		} else if img.ID != officialID {
+			t.Errorf("Expected ID '%s' found '%s'", officialID, img.ID)
 		}
 	}
 
@@ -166,8 +184,8 @@ func TestLookupImage(t *testing.T) {
 			t.Errorf("Error looking up %s: %s", name, err)
 		} else if img == nil {
 			t.Errorf("Expected 1 image, none found: %s", name)
-		} else if img.ID != testPrivateImageID {
-			t.Errorf("Expected ID '%s' found '%s'", testPrivateImageID, img.ID)
+		} else if img.ID != privateID {
+			t.Errorf("Expected ID '%s' found '%s'", privateID, img.ID)
 		}
 	}
 
@@ -184,8 +202,8 @@ func TestLookupImage(t *testing.T) {
 			t.Errorf("Error looking up %s: %s", name, err)
 		} else if img == nil {
 			t.Errorf("Expected 1 image, none found: %s", name)
-		} else if img.ID != testPrivateImageID {
-			t.Errorf("Expected ID '%s' found '%s'", testPrivateImageID, img.ID)
+		} else if img.ID != privateID {
+			t.Errorf("Expected ID '%s' found '%s'", privateID, img.ID)
 		}
 	}
 }
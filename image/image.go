@@ -1,11 +1,14 @@
 package image
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"regexp"
 	"time"
 
+	"github.com/docker/docker/layer"
 	"github.com/docker/docker/runconfig"
 )
 
@@ -13,8 +16,9 @@ var validHex = regexp.MustCompile(`^([a-f0-9]{64})$`)
 
 // Image stores the image configuration.
 type Image struct {
-	// ID a unique 64 character identifier of the image
-	ID string `json:"id"`
+	// ID a unique 64 character identifier of the image, the hex SHA-256 of
+	// its configuration
+	ID string `json:"id,omitempty"`
 	// Parent id of the image
 	Parent string `json:"parent,omitempty"`
 	// Comment user added comment
@@ -35,8 +39,34 @@ type Image struct {
 	Architecture string `json:"architecture,omitempty"`
 	// OS is the operating system used to build and run the image
 	OS string `json:"os,omitempty"`
+	// RootFS lists the layers of the image
+	RootFS *RootFS `json:"rootfs,omitempty"`
 	// Size is the total size of the image including all layers it is composed of
-	Size int64
+	Size int64 `json:",omitempty"`
+}
+
+// RootFS describes the root filesystem of an image.
+type RootFS struct {
+	// Type is the type of the root filesystem, always "layers"
+	Type string `json:"type"`
+	// DiffIDs are the digests of the layers of the image, from the base
+	// layer to the top one
+	DiffIDs []layer.DiffID `json:"diff_ids,omitempty"`
+}
+
+// RawConfig returns the configuration of the image, the JSON form of the
+// image without its ID and size, from which its ID is computed.
+func (img *Image) RawConfig() ([]byte, error) {
+	config := *img
+	config.ID = ""
+	config.Size = 0
+	return json.Marshal(&config)
+}
+
+// ComputeID returns the ID of the image with the given configuration.
+func ComputeID(config []byte) string {
+	sum := sha256.Sum256(config)
+	return hex.EncodeToString(sum[:])
 }
 
 // NewImgJSON creates an Image configuration from json.
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 
+	"github.com/docker/docker/api/types/events"
 	"github.com/go-check/check"
 )
 
@@ -27,3 +31,68 @@ func (s *DockerSuite) TestEventsApiEmptyOutput(c *check.C) {
 		c.Fatal("timeout waiting for events api to respond, should have responded immediately")
 	}
 }
+
+func (s *DockerSuite) TestEventsApiTypedMessages(c *check.C) {
+	since := daemonTime(c).Unix()
+	dockerCmd(c, "run", "--name", "typedevents", "--label", "testlabel=foo", "busybox", "sh", "-c", "exit 3")
+	until := daemonTime(c).Unix()
+
+	query := fmt.Sprintf("since=%d&until=%d&filters=%s", since, until, `{"container":["typedevents"],"event":["die"]}`)
+	_, body, err := sockRequestRaw("GET", "/events?"+query, nil, "")
+	c.Assert(err, check.IsNil)
+	defer body.Close()
+
+	var die events.Message
+	dec := json.NewDecoder(body)
+	c.Assert(dec.Decode(&die), check.IsNil)
+	c.Assert(die.Type, check.Equals, events.ContainerEventType)
+	c.Assert(die.Action, check.Equals, "die")
+	c.Assert(die.Status, check.Equals, "die")
+	c.Assert(die.ID, check.Equals, die.Actor.ID)
+	c.Assert(die.Actor.Attributes["name"], check.Equals, "typedevents")
+	c.Assert(die.Actor.Attributes["image"], check.Equals, "busybox")
+	c.Assert(die.Actor.Attributes["exitCode"], check.Equals, "3")
+	c.Assert(die.Actor.Attributes["testlabel"], check.Equals, "foo")
+	c.Assert(die.TimeNano/int64(time.Second), check.Equals, die.Time)
+	c.Assert(dec.Decode(&die), check.Equals, io.EOF)
+
+	// Older API versions only get the fields of the previous format
+	_, body, err = sockRequestRaw("GET", "/v1.20/events?"+query, nil, "")
+	c.Assert(err, check.IsNil)
+	defer body.Close()
+
+	var legacy map[string]interface{}
+	c.Assert(json.NewDecoder(body).Decode(&legacy), check.IsNil)
+	c.Assert(legacy["status"], check.Equals, "die")
+	for _, k := range []string{"Type", "Action", "Actor", "timeNano"} {
+		_, ok := legacy[k]
+		c.Assert(ok, check.Equals, false, check.Commentf("unexpected %s in %v", k, legacy))
+	}
+}
+
+func (s *DockerSuite) TestEventsApiFilterType(c *check.C) {
+	since := daemonTime(c).Unix()
+	dockerCmd(c, "volume", "create", "--name", "typedeventsvolume")
+	dockerCmd(c, "run", "--rm", "busybox", "true")
+	until := daemonTime(c).Unix()
+
+	query := fmt.Sprintf("since=%d&until=%d&filters=%s", since, until, `{"type":["volume"]}`)
+	_, body, err := sockRequestRaw("GET", "/events?"+query, nil, "")
+	c.Assert(err, check.IsNil)
+	defer body.Close()
+
+	dec := json.NewDecoder(body)
+	found := false
+	for {
+		var ev events.Message
+		if err := dec.Decode(&ev); err != nil {
+			c.Assert(err, check.Equals, io.EOF)
+			break
+		}
+		c.Assert(ev.Type, check.Equals, events.VolumeEventType, check.Commentf("%v", ev))
+		if ev.Actor.ID == "typedeventsvolume" && ev.Action == "create" {
+			found = true
+		}
+	}
+	c.Assert(found, check.Equals, true)
+}
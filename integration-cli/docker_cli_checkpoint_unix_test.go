@@ -0,0 +1,31 @@
+// +build !windows
+
+package main
+
+import (
+	"strings"
+
+	"github.com/go-check/check"
+)
+
+func (s *DockerSuite) TestCheckpointStoppedContainer(c *check.C) {
+	name := "test-checkpoint-stopped"
+	dockerCmd(c, "run", "--name", name, "busybox", "true")
+
+	out, _, err := dockerCmdWithError("checkpoint", name)
+	c.Assert(err, check.NotNil)
+	c.Assert(strings.Contains(out, "is not running"), check.Equals, true, check.Commentf("Unexpected output: %s", out))
+}
+
+func (s *DockerSuite) TestRestoreNotCheckpointed(c *check.C) {
+	name := "test-restore-not-checkpointed"
+	dockerCmd(c, "run", "--name", name, "busybox", "true")
+
+	out, _, err := dockerCmdWithError("restore", name)
+	c.Assert(err, check.NotNil)
+	c.Assert(strings.Contains(out, "is not checkpointed"), check.Equals, true, check.Commentf("Unexpected output: %s", out))
+
+	checkpointed, err := inspectField(name, "State.Checkpointed")
+	c.Assert(err, check.IsNil)
+	c.Assert(checkpointed, check.Equals, "false")
+}
@@ -13,6 +13,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/docker/libnetwork/iptables"
@@ -59,6 +60,111 @@ func (s *DockerDaemonSuite) TestDaemonRestartWithRunningContainersPorts(c *check
 	testRun(map[string]bool{"top1": true, "top2": false}, "After daemon restart: ")
 }
 
+func (s *DockerDaemonSuite) TestDaemonRestartUnlessStopped(c *check.C) {
+	c.Assert(s.d.StartWithBusybox(), check.IsNil)
+
+	for _, name := range []string{"top1", "top2"} {
+		out, err := s.d.Cmd("run", "-d", "--name", name, "--restart", "unless-stopped", "busybox:latest", "top")
+		c.Assert(err, check.IsNil, check.Commentf(out))
+	}
+	out, err := s.d.Cmd("stop", "top2")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+
+	c.Assert(s.d.Restart(), check.IsNil)
+
+	// only the container that was not stopped by the user is started again
+	out, err = s.d.Cmd("ps")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.Contains(out, "top1"), check.Equals, true, check.Commentf("top1 is not running: %s", out))
+	c.Assert(strings.Contains(out, "top2"), check.Equals, false, check.Commentf("top2 is running: %s", out))
+}
+
+func (s *DockerDaemonSuite) TestDaemonLiveRestore(c *check.C) {
+	testRequires(c, NativeExecDriver)
+	c.Assert(s.d.StartWithBusybox("--live-restore"), check.IsNil)
+
+	out, err := s.d.Cmd("run", "-d", "--name", "live", "busybox:latest", "sh", "-c", "echo started; while true; do sleep 1; done")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	pid, err := s.d.Cmd("inspect", "-f", "{{.State.Pid}}", "live")
+	c.Assert(err, check.IsNil, check.Commentf(pid))
+
+	c.Assert(s.d.Restart("--live-restore"), check.IsNil)
+
+	// the process of the container kept running and is reattached
+	out, err = s.d.Cmd("inspect", "-f", "{{.State.Running}} {{.State.Pid}}", "live")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), check.Equals, "true "+strings.TrimSpace(pid))
+
+	out, err = s.d.Cmd("stop", "live")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	out, err = s.d.Cmd("inspect", "-f", "{{.State.Running}}", "live")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), check.Equals, "false")
+
+	out, err = s.d.Cmd("logs", "live")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.Contains(out, "started"), check.Equals, true, check.Commentf(out))
+}
+
+func (s *DockerDaemonSuite) TestDaemonLiveRestoreDisabled(c *check.C) {
+	testRequires(c, NativeExecDriver)
+	c.Assert(s.d.StartWithBusybox("--live-restore"), check.IsNil)
+
+	out, err := s.d.Cmd("run", "-d", "--name", "live", "busybox:latest", "top")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+
+	// a daemon started without the option kills the containers left running
+	c.Assert(s.d.Restart(), check.IsNil)
+	out, err = s.d.Cmd("inspect", "-f", "{{.State.Running}} {{.State.ExitCode}}", "live")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), check.Equals, "false 137")
+}
+
+func (s *DockerDaemonSuite) TestDaemonConfigFileReload(c *check.C) {
+	testRequires(c, SameHostDaemon)
+	configFile := filepath.Join(s.d.folder, "daemon.json")
+	err := ioutil.WriteFile(configFile, []byte(`{"label": ["env=prod"], "log-opt": {"max-size": "10m"}}`), 0600)
+	c.Assert(err, check.IsNil)
+	c.Assert(s.d.Start("--config-file", configFile, "--label", "host=test", "--log-level=info"), check.IsNil)
+
+	out, err := s.d.Cmd("info")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.Contains(out, "env=prod"), check.Equals, true, check.Commentf(out))
+	c.Assert(strings.Contains(out, "host=test"), check.Equals, true, check.Commentf(out))
+
+	// the labels given on the command line are not reloaded
+	err = ioutil.WriteFile(configFile, []byte(`{"label": ["env=staging"], "debug": true}`), 0600)
+	c.Assert(err, check.IsNil)
+	c.Assert(s.d.cmd.Process.Signal(syscall.SIGHUP), check.IsNil)
+
+	var events string
+	for i := 0; i < 10 && !strings.Contains(events, "reload"); i++ {
+		time.Sleep(500 * time.Millisecond)
+		events, err = s.d.Cmd("events", "--since=0", fmt.Sprintf("--until=%d", time.Now().Unix()), "--filter", "type=daemon")
+		c.Assert(err, check.IsNil, check.Commentf(events))
+	}
+	c.Assert(strings.Contains(events, "daemon reload"), check.Equals, true, check.Commentf(events))
+	c.Assert(strings.Contains(events, "debug=true"), check.Equals, true, check.Commentf(events))
+	c.Assert(strings.Contains(events, "log-opt="), check.Equals, true, check.Commentf(events))
+
+	out, err = s.d.Cmd("info")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.Contains(out, "env=prod"), check.Equals, true, check.Commentf(out))
+	c.Assert(strings.Contains(out, "host=test"), check.Equals, true, check.Commentf(out))
+	c.Assert(strings.Contains(out, "Debug mode (server): true"), check.Equals, true, check.Commentf(out))
+}
+
+func (s *DockerDaemonSuite) TestDaemonConfigFileConflict(c *check.C) {
+	configFile := filepath.Join(s.d.folder, "daemon.json")
+	err := ioutil.WriteFile(configFile, []byte(`{"label": ["env=prod"]}`), 0600)
+	c.Assert(err, check.IsNil)
+	c.Assert(s.d.Start("--config-file", configFile, "--label", "env=test"), check.NotNil)
+
+	content, err := ioutil.ReadFile(s.d.logFile.Name())
+	c.Assert(err, check.IsNil)
+	c.Assert(strings.Contains(string(content), "given both as flags and in the configuration file: label"), check.Equals, true, check.Commentf(string(content)))
+}
+
 func (s *DockerDaemonSuite) TestDaemonRestartWithVolumesRefs(c *check.C) {
 	if err := s.d.StartWithBusybox(); err != nil {
 		c.Fatal(err)
@@ -1550,3 +1656,26 @@ func (s *DockerDaemonSuite) TestDaemonRestartWithContainerWithRestartPolicyAlway
 	c.Assert(err, check.IsNil)
 	c.Assert(strings.TrimSpace(out), check.Equals, id[:12])
 }
+
+func (s *DockerDaemonSuite) TestDaemonRestartKeepsEvents(c *check.C) {
+	c.Assert(s.d.StartWithBusybox(), check.IsNil)
+
+	out, err := s.d.Cmd("run", "--name", "journaltest", "busybox", "true")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	// Log more events than the daemon keeps in memory
+	for i := 0; i < 70; i++ {
+		out, err := s.d.Cmd("tag", "busybox", fmt.Sprintf("journaltest:%d", i))
+		c.Assert(err, check.IsNil, check.Commentf(out))
+	}
+
+	c.Assert(s.d.Restart(), check.IsNil)
+
+	out, err = s.d.Cmd("events", "--since=0", fmt.Sprintf("--until=%d", time.Now().Unix()), "--filter", "container=journaltest")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.Contains(out, " create\n"), check.Equals, true, check.Commentf("missing create event in %s", out))
+	c.Assert(strings.Contains(out, " die\n"), check.Equals, true, check.Commentf("missing die event in %s", out))
+
+	out, err = s.d.Cmd("events", "--since=0", fmt.Sprintf("--until=%d", time.Now().Unix()), "--filter", "event=tag")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.Count(out, "\n"), check.Equals, 70, check.Commentf("Events == %s", out))
+}
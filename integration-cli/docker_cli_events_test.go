@@ -667,3 +667,59 @@ func (s *DockerRegistrySuite) TestEventsImageFilterPush(c *check.C) {
 		c.Fatalf("Missing 'push' log event for image %s\n%s", repoName, out)
 	}
 }
+
+func (s *DockerSuite) TestEventsFilterType(c *check.C) {
+	since := daemonTime(c).Unix()
+	name := "labelfiltertest"
+	label := "io.docker.testing=image"
+
+	// Build a test image.
+	_, err := buildImage(name, fmt.Sprintf(`
+		FROM busybox:latest
+		LABEL %s`, label), true)
+	c.Assert(err, check.IsNil)
+
+	dockerCmd(c, "tag", name, "labelfiltertest:tag1")
+	dockerCmd(c, "run", "--name", name, "--label", "io.docker.testing=container", "busybox", "true")
+
+	out, _ := dockerCmd(c, "events",
+		fmt.Sprintf("--since=%d", since),
+		fmt.Sprintf("--until=%d", daemonTime(c).Unix()),
+		"--filter", "type=image")
+	events := strings.Split(strings.TrimSpace(out), "\n")
+	c.Assert(len(events), check.Equals, 1, check.Commentf("Events == %s", events))
+	c.Assert(strings.HasSuffix(events[0], "labelfiltertest:tag1: tag"), check.Equals, true, check.Commentf("Events == %s", events))
+
+	out, _ = dockerCmd(c, "events",
+		fmt.Sprintf("--since=%d", since),
+		fmt.Sprintf("--until=%d", daemonTime(c).Unix()),
+		"--filter", "type=container",
+		"--filter", "label=io.docker.testing=container",
+		"--filter", "event=die")
+	events = strings.Split(strings.TrimSpace(out), "\n")
+	c.Assert(len(events), check.Equals, 1, check.Commentf("Events == %s", events))
+	c.Assert(strings.HasSuffix(events[0], " die"), check.Equals, true, check.Commentf("Events == %s", events))
+
+	out, _ = dockerCmd(c, "events",
+		fmt.Sprintf("--since=%d", since),
+		fmt.Sprintf("--until=%d", daemonTime(c).Unix()),
+		"--filter", "label=io.docker.testing=image")
+	c.Assert(strings.Contains(out, "labelfiltertest:tag1: tag"), check.Equals, true, check.Commentf("Events == %s", out))
+	c.Assert(strings.Contains(out, " die"), check.Equals, false, check.Commentf("Events == %s", out))
+}
+
+func (s *DockerSuite) TestEventsVolume(c *check.C) {
+	since := daemonTime(c).Unix()
+
+	dockerCmd(c, "volume", "create", "--name", "testeventsvolume")
+	dockerCmd(c, "volume", "rm", "testeventsvolume")
+
+	out, _ := dockerCmd(c, "events",
+		fmt.Sprintf("--since=%d", since),
+		fmt.Sprintf("--until=%d", daemonTime(c).Unix()),
+		"--filter", "volume=testeventsvolume")
+	events := strings.Split(strings.TrimSpace(out), "\n")
+	c.Assert(len(events), check.Equals, 2, check.Commentf("Events == %s", events))
+	c.Assert(strings.Contains(events[0], "volume create testeventsvolume (driver=local)"), check.Equals, true, check.Commentf("Events == %s", events))
+	c.Assert(strings.Contains(events[1], "volume destroy testeventsvolume (driver=local)"), check.Equals, true, check.Commentf("Events == %s", events))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/runconfig"
+	"github.com/go-check/check"
+)
+
+func (s *DockerSuite) TestHealthBuildConfig(c *check.C) {
+	name := "testhealthbuild"
+	_, err := buildImage(name,
+		`FROM busybox
+		HEALTHCHECK --interval=1s --timeout=30s --retries=2 CMD cat /status`,
+		true)
+	c.Assert(err, check.IsNil)
+
+	out, err := inspectFieldJSON(name, "Config.Healthcheck")
+	c.Assert(err, check.IsNil)
+
+	var health runconfig.HealthConfig
+	c.Assert(json.Unmarshal([]byte(out), &health), check.IsNil)
+	c.Assert(strings.Join(health.Test, " "), check.Equals, "CMD-SHELL cat /status")
+	c.Assert(health.Interval.String(), check.Equals, "1s")
+	c.Assert(health.Timeout.String(), check.Equals, "30s")
+	c.Assert(health.Retries, check.Equals, 2)
+
+	// NONE disables an inherited healthcheck
+	_, err = buildImage("testhealthbuildnone",
+		`FROM `+name+`
+		HEALTHCHECK NONE`,
+		true)
+	c.Assert(err, check.IsNil)
+
+	out, err = inspectField("testhealthbuildnone", "Config.Healthcheck.Test")
+	c.Assert(err, check.IsNil)
+	c.Assert(out, check.Equals, "[NONE]")
+}
+
+func (s *DockerSuite) TestHealthRun(c *check.C) {
+	testRequires(c, ExecSupport)
+	imageName := "testhealth"
+	_, err := buildImage(imageName,
+		`FROM busybox
+		RUN echo OK > /status
+		HEALTHCHECK --interval=1s --timeout=30s --retries=1 \
+		  CMD cat /status`,
+		true)
+	c.Assert(err, check.IsNil)
+
+	name := "test_health"
+	dockerCmd(c, "run", "-d", "--name", name, imageName, "top")
+
+	c.Assert(waitInspect(name, "{{.State.Health.Status}}", "healthy", 10), check.IsNil)
+
+	// Break the check
+	dockerCmd(c, "exec", name, "rm", "/status")
+	c.Assert(waitInspect(name, "{{.State.Health.Status}}", "unhealthy", 10), check.IsNil)
+
+	out, err := inspectFieldJSON(name, "State.Health")
+	c.Assert(err, check.IsNil)
+
+	var health types.Health
+	c.Assert(json.Unmarshal([]byte(out), &health), check.IsNil)
+	c.Assert(health.FailingStreak, check.Not(check.Equals), 0)
+	last := health.Log[len(health.Log)-1]
+	c.Assert(last.ExitCode, check.Equals, 1)
+	c.Assert(last.Output, check.Equals, "cat: can't open '/status': No such file or directory\n")
+
+	// Fix it again
+	dockerCmd(c, "exec", name, "touch", "/status")
+	c.Assert(waitInspect(name, "{{.State.Health.Status}}", "healthy", 10), check.IsNil)
+
+	out, _ = dockerCmd(c, "events", "--since=0", fmt.Sprintf("--until=%d", daemonTime(c).Unix()), "--filter", "container="+name)
+	c.Assert(strings.Contains(out, "health_status: unhealthy"), check.Equals, true, check.Commentf("missing unhealthy event in %s", out))
+	c.Assert(strings.Contains(out, "health_status: healthy"), check.Equals, true, check.Commentf("missing healthy event in %s", out))
+}
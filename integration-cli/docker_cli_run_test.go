@@ -2781,3 +2781,58 @@ func (s *DockerSuite) TestRunCapAddSYSTIME(c *check.C) {
 
 	dockerCmd(c, "run", "--cap-drop=ALL", "--cap-add=SYS_TIME", "busybox", "sh", "-c", "grep ^CapEff /proc/self/status | sed 's/^CapEff:\t//' | grep ^0000000002000000$")
 }
+
+func (s *DockerSuite) TestRunStopSignal(c *check.C) {
+	name := "test-stop-signal"
+	dockerCmd(c, "run", "-d", "--name", name, "--stop-signal", "SIGQUIT", "busybox",
+		"sh", "-c", "trap 'exit 7' QUIT; while true; do sleep 1; done")
+
+	res, err := inspectField(name, "Config.StopSignal")
+	c.Assert(err, check.IsNil)
+	c.Assert(res, check.Equals, "SIGQUIT")
+
+	dockerCmd(c, "stop", name)
+
+	exitCode, err := inspectField(name, "State.ExitCode")
+	c.Assert(err, check.IsNil)
+	c.Assert(exitCode, check.Equals, "7")
+}
+
+func (s *DockerSuite) TestRunInvalidStopSignal(c *check.C) {
+	out, _, err := dockerCmdWithError("run", "--stop-signal", "SIGNOPE", "busybox", "true")
+	c.Assert(err, check.NotNil)
+	c.Assert(strings.Contains(out, "Invalid signal: SIGNOPE"), check.Equals, true, check.Commentf("unexpected output %s", out))
+}
+
+func (s *DockerSuite) TestRunAutoRemoveExitCode(c *check.C) {
+	name := "test-autoremove-exitcode"
+	_, exitCode, err := dockerCmdWithError("run", "--rm", "--name", name, "busybox", "sh", "-c", "exit 3")
+	c.Assert(err, check.NotNil)
+	c.Assert(exitCode, check.Equals, 3)
+
+	out, _ := dockerCmd(c, "ps", "-a", "-q", "--filter", "name="+name)
+	c.Assert(strings.TrimSpace(out), check.Equals, "")
+}
+
+func (s *DockerSuite) TestRunDetachedAutoRemove(c *check.C) {
+	out, _ := dockerCmd(c, "run", "-d", "--rm", "-v", "/data", "busybox", "true")
+	id := strings.TrimSpace(out)
+
+	// the container is removed by the daemon once it exits
+	for i := 0; ; i++ {
+		out, _ = dockerCmd(c, "ps", "-a", "-q", "--no-trunc")
+		if !strings.Contains(out, id) {
+			break
+		}
+		if i == 50 {
+			c.Fatalf("Container %s was not removed after it exited", id)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (s *DockerSuite) TestRunAutoRemoveRestartPolicy(c *check.C) {
+	out, _, err := dockerCmdWithError("run", "--rm", "--restart", "always", "busybox", "true")
+	c.Assert(err, check.NotNil)
+	c.Assert(strings.Contains(out, "Conflicting options"), check.Equals, true, check.Commentf("Unexpected output: %s", out))
+}
@@ -283,6 +283,32 @@ func (s *DockerSuite) TestRunWithCpuPeriod(c *check.C) {
 	}
 }
 
+func (s *DockerSuite) TestRunWithInvalidThrottleDevice(c *check.C) {
+	for _, args := range [][]string{
+		{"--device-read-bps", "/dev/sda:-1"},
+		{"--device-write-bps", "sda:1mb"},
+		{"--device-read-iops", "/dev/sda:1mb"},
+		{"--device-write-iops", "/dev/sda"},
+	} {
+		out, _, err := dockerCmdWithError(append(append([]string{"run"}, args...), "busybox", "true")...)
+		c.Assert(err, check.NotNil, check.Commentf("%v should be rejected", args))
+		if !strings.Contains(out, "invalid rate for device") && !strings.Contains(out, "bad format") {
+			c.Fatalf("Expected an error about the device format for %v, got %s", args, out)
+		}
+	}
+}
+
+func (s *DockerSuite) TestRunWithPidsLimit(c *check.C) {
+	testRequires(c, pidsLimit)
+
+	out, _ := dockerCmd(c, "run", "--pids-limit", "10", "--name", "test", "busybox", "cat", "/sys/fs/cgroup/pids/pids.max")
+	c.Assert(strings.TrimSpace(out), check.Equals, "10")
+
+	out, err := inspectField("test", "HostConfig.PidsLimit")
+	c.Assert(err, check.IsNil)
+	c.Assert(out, check.Equals, "10")
+}
+
 func (s *DockerSuite) TestRunOOMExitCode(c *check.C) {
 	testRequires(c, oomControl)
 	errChan := make(chan error)
@@ -414,3 +440,38 @@ func (s *DockerSuite) TestTwoContainersInNetHost(c *check.C) {
 	dockerCmd(c, "stop", "first")
 	dockerCmd(c, "stop", "second")
 }
+
+func (s *DockerSuite) TestRunSeccompProfile(c *check.C) {
+	testRequires(c, NativeExecDriver)
+
+	tmpFile, err := ioutil.TempFile("", "profile.json")
+	c.Assert(err, check.IsNil)
+	defer os.Remove(tmpFile.Name())
+	profile := `{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [{"name": "chmod", "action": "SCMP_ACT_ERRNO"}]}`
+	_, err = tmpFile.WriteString(profile)
+	c.Assert(err, check.IsNil)
+	tmpFile.Close()
+
+	out, _, err := dockerCmdWithError("run", "--security-opt", "seccomp="+tmpFile.Name(), "busybox", "chmod", "400", "/etc/hostname")
+	c.Assert(err, check.NotNil, check.Commentf("chmod should be denied by the seccomp profile: %s", out))
+	c.Assert(strings.Contains(out, "Operation not permitted"), check.Equals, true, check.Commentf("unexpected output: %s", out))
+
+	dockerCmd(c, "run", "--name", "unconfined", "--security-opt", "seccomp=unconfined", "busybox", "chmod", "400", "/etc/hostname")
+	profile, err = inspectField("unconfined", "SeccompProfile")
+	c.Assert(err, check.IsNil)
+	c.Assert(profile, check.Equals, "unconfined")
+}
+
+func (s *DockerSuite) TestRunSeccompDefaultProfile(c *check.C) {
+	testRequires(c, NativeExecDriver)
+
+	// SYS_ADMIN would allow the mount without the seccomp filter
+	out, _, err := dockerCmdWithError("run", "--name", "confined", "--cap-add", "SYS_ADMIN", "busybox", "mount", "-t", "tmpfs", "tmpfs", "/tmp")
+	c.Assert(err, check.NotNil, check.Commentf("mount should be denied by the default seccomp profile: %s", out))
+	profile, err := inspectField("confined", "SeccompProfile")
+	c.Assert(err, check.IsNil)
+	c.Assert(profile, check.Equals, "default")
+
+	// privileged containers are not confined
+	dockerCmd(c, "run", "--privileged", "busybox", "mount", "-t", "tmpfs", "tmpfs", "/tmp")
+}
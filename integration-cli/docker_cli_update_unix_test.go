@@ -0,0 +1,61 @@
+// +build !windows
+
+package main
+
+import (
+	"strings"
+
+	"github.com/go-check/check"
+)
+
+func (s *DockerSuite) TestUpdateRunningContainer(c *check.C) {
+	testRequires(c, NativeExecDriver)
+
+	name := "test-update-container"
+	dockerCmd(c, "run", "-d", "--name", name, "-m", "300M", "busybox", "top")
+	dockerCmd(c, "update", "-m", "500M", "--cpu-shares", "512", name)
+
+	memory, err := inspectField(name, "HostConfig.Memory")
+	c.Assert(err, check.IsNil)
+	c.Assert(memory, check.Equals, "524288000")
+	shares, err := inspectField(name, "HostConfig.CpuShares")
+	c.Assert(err, check.IsNil)
+	c.Assert(shares, check.Equals, "512")
+
+	out, _ := dockerCmd(c, "exec", name, "cat", "/sys/fs/cgroup/memory/memory.limit_in_bytes")
+	c.Assert(strings.TrimSpace(out), check.Equals, "524288000")
+	out, _ = dockerCmd(c, "exec", name, "cat", "/sys/fs/cgroup/cpu/cpu.shares")
+	c.Assert(strings.TrimSpace(out), check.Equals, "512")
+
+	// the new limits are kept when the container is restarted
+	dockerCmd(c, "restart", name)
+	out, _ = dockerCmd(c, "exec", name, "cat", "/sys/fs/cgroup/memory/memory.limit_in_bytes")
+	c.Assert(strings.TrimSpace(out), check.Equals, "524288000")
+}
+
+func (s *DockerSuite) TestUpdateStoppedContainer(c *check.C) {
+	name := "test-update-stopped"
+	dockerCmd(c, "create", "--name", name, "busybox", "true")
+	dockerCmd(c, "update", "--cpuset-cpus", "0", name)
+
+	cpus, err := inspectField(name, "HostConfig.CpusetCpus")
+	c.Assert(err, check.IsNil)
+	c.Assert(cpus, check.Equals, "0")
+}
+
+func (s *DockerSuite) TestUpdateInvalidLimits(c *check.C) {
+	name := "test-update-invalid"
+	dockerCmd(c, "create", "--name", name, "-m", "300M", "busybox", "true")
+
+	out, _, err := dockerCmdWithError("update", "--blkio-weight", "5", name)
+	c.Assert(err, check.NotNil, check.Commentf("expected the blkio weight to be rejected: %s", out))
+	out, _, err = dockerCmdWithError("update", "-m", "2M", name)
+	c.Assert(err, check.NotNil, check.Commentf("expected the memory limit to be rejected: %s", out))
+
+	memory, err := inspectField(name, "HostConfig.Memory")
+	c.Assert(err, check.IsNil)
+	c.Assert(memory, check.Equals, "314572800")
+
+	_, _, err = dockerCmdWithError("update", name)
+	c.Assert(err, check.NotNil)
+}
@@ -54,4 +54,11 @@ var (
 		},
 		"Test requires Oom control enabled.",
 	}
+	pidsLimit = testRequirement{
+		func() bool {
+			_, err := cgroups.FindCgroupMountpoint("pids")
+			return err == nil
+		},
+		"Test requires pids limit support.",
+	}
 )
@@ -0,0 +1,171 @@
+// Package layer stores the filesystem layers of the images by content.
+//
+// A layer is identified by its chain ID, which is computed from the diff ID
+// of the layer, the digest of its uncompressed tar stream, and the chain ID
+// of its parent. Two images made of the same layers on top of each other
+// therefore share them, whatever their own IDs. The store keeps a count of
+// the references to each layer and removes it from the storage driver when
+// the last one is released.
+package layer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/pkg/archive"
+)
+
+var (
+	// ErrLayerDoesNotExist is returned when a layer is not in the store.
+	ErrLayerDoesNotExist = errors.New("layer does not exist")
+)
+
+// DiffID is the digest of the uncompressed tar stream of a layer.
+type DiffID digest.Digest
+
+// String returns the string form of the diff ID.
+func (id DiffID) String() string {
+	return string(id)
+}
+
+// ChainID identifies a layer and all of its parents.
+type ChainID digest.Digest
+
+// String returns the string form of the chain ID.
+func (id ChainID) String() string {
+	return string(id)
+}
+
+// CreateChainID returns the chain ID of the layer with the given diff ID on
+// top of the layer parent. The chain ID of a layer without parent is its
+// diff ID, the one of any other layer is the SHA-256 of the string
+// "<parent chain ID> <diff ID>".
+func CreateChainID(parent ChainID, diffID DiffID) ChainID {
+	if parent == "" {
+		return ChainID(diffID)
+	}
+	dgst, _ := digest.FromBytes([]byte(parent.String() + " " + diffID.String()))
+	return ChainID(dgst)
+}
+
+// CreateChainIDFromDiffIDs returns the chain ID of the layers with the given
+// diff IDs, from the bottom one to the top one.
+func CreateChainIDFromDiffIDs(diffIDs []DiffID) ChainID {
+	var chainID ChainID
+	for _, diffID := range diffIDs {
+		chainID = CreateChainID(chainID, diffID)
+	}
+	return chainID
+}
+
+// ChainDriver is implemented by the storage drivers, such as the Windows
+// one, that apply and export a layer against the paths of all of its
+// parents rather than a single parent. The parents are given from the
+// nearest one to the base layer.
+type ChainDriver interface {
+	ApplyDiffChain(id string, parents []string, diff archive.ArchiveReader) (int64, error)
+	DiffChain(id string, parents []string) (archive.Archive, error)
+}
+
+// Layer is a filesystem layer held by the store.
+type Layer struct {
+	store      *Store
+	chainID    ChainID
+	diffID     DiffID
+	parent     *Layer
+	cacheID    string
+	size       int64
+	references int
+}
+
+// ChainID returns the ID of the layer in the store.
+func (l *Layer) ChainID() ChainID {
+	return l.chainID
+}
+
+// DiffID returns the digest of the tar stream of the layer.
+func (l *Layer) DiffID() DiffID {
+	return l.diffID
+}
+
+// Parent returns the layer below, or nil for a base layer.
+func (l *Layer) Parent() *Layer {
+	return l.parent
+}
+
+// CacheID returns the ID of the layer in the storage driver.
+func (l *Layer) CacheID() string {
+	return l.cacheID
+}
+
+// Size returns the size of the changes of the layer.
+func (l *Layer) Size() int64 {
+	return l.size
+}
+
+// DiffIDs returns the diff IDs of the layer and of its parents, from the
+// base layer to this one.
+func (l *Layer) DiffIDs() []DiffID {
+	var diffIDs []DiffID
+	for p := l; p != nil; p = p.parent {
+		diffIDs = append([]DiffID{p.diffID}, diffIDs...)
+	}
+	return diffIDs
+}
+
+// parentCacheIDs returns the cache IDs of the parents of the layer, from
+// the nearest one to the base layer.
+func (l *Layer) parentCacheIDs() []string {
+	var ids []string
+	for p := l.parent; p != nil; p = p.parent {
+		ids = append(ids, p.cacheID)
+	}
+	return ids
+}
+
+func (l *Layer) parentCacheID() string {
+	if l.parent == nil {
+		return ""
+	}
+	return l.parent.cacheID
+}
+
+// TarStream returns the uncompressed tar stream of the layer. Reading it
+// fails when the stream does not match the diff ID of the layer, except
+// with a ChainDriver, which does not give back the stream the layer was
+// created from.
+func (l *Layer) TarStream() (io.ReadCloser, error) {
+	if cd, ok := l.store.driver.(ChainDriver); ok {
+		return cd.DiffChain(l.cacheID, l.parentCacheIDs())
+	}
+	rc, err := l.store.assembleTarStream(l)
+	if err != nil {
+		return nil, err
+	}
+	return &verifiedReader{rc: rc, digester: digest.Canonical.New(), expected: l.diffID}, nil
+}
+
+// verifiedReader checks the digest of what is read from rc once it is
+// fully read.
+type verifiedReader struct {
+	rc       io.ReadCloser
+	digester digest.Digester
+	expected DiffID
+}
+
+func (r *verifiedReader) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	r.digester.Hash().Write(p[:n])
+	if err == io.EOF {
+		if dgst := DiffID(r.digester.Digest()); dgst != r.expected {
+			return n, fmt.Errorf("layer content %s does not match its diff ID %s", dgst, r.expected)
+		}
+	}
+	return n, err
+}
+
+func (r *verifiedReader) Close() error {
+	return r.rc.Close()
+}
@@ -0,0 +1,472 @@
+package layer
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/daemon/graphdriver"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/pkg/system"
+	"github.com/vbatts/tar-split/tar/asm"
+	"github.com/vbatts/tar-split/tar/storage"
+)
+
+// file names for ./<algorithm>/<hex>/
+const (
+	diffFileName     = "diff"
+	sizeFileName     = "size"
+	cacheIDFileName  = "cache-id"
+	parentFileName   = "parent"
+	tarSplitFileName = "tar-split.json.gz"
+)
+
+// Store holds the layers of a storage driver, keyed by chain ID, under a
+// root directory. Each layer has a directory named after its chain ID,
+// which holds its diff ID, size, storage driver ID, parent chain ID and the
+// tar-split metadata used to rebuild its tar stream.
+type Store struct {
+	root   string
+	driver graphdriver.Driver
+
+	mu     sync.Mutex
+	layers map[ChainID]*Layer
+}
+
+// NewStore loads the layers stored under root for driver. They start
+// without any reference but the ones of their children: the owner of the
+// store acquires the layers it uses and then prunes the others.
+func NewStore(root string, driver graphdriver.Driver) (*Store, error) {
+	// Drop the layers left over by an interrupted registration.
+	if err := os.RemoveAll(filepath.Join(root, "tmp")); err != nil {
+		return nil, err
+	}
+	if err := system.MkdirAll(filepath.Join(root, string(digest.Canonical)), 0700); err != nil {
+		return nil, err
+	}
+	s := &Store{
+		root:   root,
+		driver: driver,
+		layers: make(map[ChainID]*Layer),
+	}
+	if err := s.restore(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) restore() error {
+	dir, err := ioutil.ReadDir(filepath.Join(s.root, string(digest.Canonical)))
+	if err != nil {
+		return err
+	}
+	parents := make(map[ChainID]ChainID)
+	for _, v := range dir {
+		chainID := ChainID(digest.NewDigestFromHex(string(digest.Canonical), v.Name()))
+		l, parent, err := s.loadLayer(chainID)
+		if err != nil {
+			logrus.Errorf("Failed to load layer %s: %v", chainID, err)
+			continue
+		}
+		s.layers[chainID] = l
+		parents[chainID] = parent
+	}
+
+	// Link the layers to their parents, dropping the ones whose parent is
+	// gone.
+	var link func(l *Layer) bool
+	link = func(l *Layer) bool {
+		parentID, ok := parents[l.chainID]
+		if !ok {
+			return true
+		}
+		delete(parents, l.chainID)
+		if parentID == "" {
+			return true
+		}
+		if p, exists := s.layers[parentID]; exists && link(p) {
+			l.parent = p
+			p.references++
+			return true
+		}
+		logrus.Errorf("Parent %s of layer %s does not exist", parentID, l.chainID)
+		delete(s.layers, l.chainID)
+		return false
+	}
+	for _, l := range s.layers {
+		link(l)
+	}
+	logrus.Debugf("Restored %d layers", len(s.layers))
+	return nil
+}
+
+func (s *Store) loadLayer(chainID ChainID) (*Layer, ChainID, error) {
+	root := s.layerRoot(chainID)
+	l := &Layer{store: s, chainID: chainID}
+
+	buf, err := ioutil.ReadFile(filepath.Join(root, diffFileName))
+	if err != nil {
+		return nil, "", err
+	}
+	dgst, err := digest.ParseDigest(strings.TrimSpace(string(buf)))
+	if err != nil {
+		return nil, "", err
+	}
+	l.diffID = DiffID(dgst)
+
+	if buf, err = ioutil.ReadFile(filepath.Join(root, sizeFileName)); err != nil {
+		return nil, "", err
+	}
+	if l.size, err = strconv.ParseInt(strings.TrimSpace(string(buf)), 10, 64); err != nil {
+		return nil, "", err
+	}
+
+	if buf, err = ioutil.ReadFile(filepath.Join(root, cacheIDFileName)); err != nil {
+		return nil, "", err
+	}
+	l.cacheID = strings.TrimSpace(string(buf))
+
+	var parent ChainID
+	if buf, err = ioutil.ReadFile(filepath.Join(root, parentFileName)); err == nil {
+		if dgst, err = digest.ParseDigest(strings.TrimSpace(string(buf))); err != nil {
+			return nil, "", err
+		}
+		parent = ChainID(dgst)
+	} else if !os.IsNotExist(err) {
+		return nil, "", err
+	}
+
+	if CreateChainID(parent, l.diffID) != chainID {
+		return nil, "", fmt.Errorf("chain ID does not match the diff ID %s and the parent %q", l.diffID, parent)
+	}
+	return l, parent, nil
+}
+
+// Register creates a layer on top of parent from the tar stream ts, which
+// may be compressed, and returns it with a reference taken. When the
+// resulting layer is already in the store, the new copy is dropped and a
+// reference to the existing layer is returned instead.
+func (s *Store) Register(ts io.Reader, parent ChainID) (*Layer, error) {
+	return s.register(stringid.GenerateRandomID(), parent, ts, true)
+}
+
+// RegisterExisting adds to the store the layer already held by the storage
+// driver as cacheID, on top of parent, and returns it with a reference
+// taken. Its diff ID is computed from ts, or from the changes found by the
+// storage driver when ts is nil. The storage driver layer is left alone
+// when the store already has the same layer.
+func (s *Store) RegisterExisting(cacheID string, parent ChainID, ts io.Reader) (*Layer, error) {
+	return s.register(cacheID, parent, ts, false)
+}
+
+func (s *Store) register(cacheID string, parentID ChainID, ts io.Reader, apply bool) (l *Layer, err error) {
+	var p *Layer
+	if parentID != "" {
+		if p, err = s.Acquire(parentID); err != nil {
+			return nil, fmt.Errorf("Failed to get parent layer %s: %v", parentID, err)
+		}
+		defer func() {
+			if err != nil {
+				s.Release(p)
+			}
+		}()
+	}
+	l = &Layer{store: s, parent: p, cacheID: cacheID}
+
+	if err := system.MkdirAll(filepath.Join(s.root, "tmp"), 0700); err != nil {
+		return nil, err
+	}
+	tmp, err := ioutil.TempDir(filepath.Join(s.root, "tmp"), "")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	if apply {
+		// The returned `error` must be named in this function's signature so
+		// that `err` is not shadowed in this deferred cleanup.
+		defer func() {
+			if err != nil {
+				s.driver.Remove(cacheID)
+			}
+		}()
+		if err = s.applyTarStream(l, ts, tmp); err != nil {
+			return nil, err
+		}
+	} else if err = s.digestTarStream(l, ts, tmp); err != nil {
+		return nil, err
+	}
+	l.chainID = CreateChainID(parentID, l.diffID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.layers[l.chainID]; ok {
+		logrus.Debugf("Layer %s already exists", l.chainID)
+		existing.references++
+		if p != nil {
+			s.releaseLocked(p)
+		}
+		if apply {
+			s.driver.Remove(cacheID)
+		}
+		return existing, nil
+	}
+
+	if err = l.saveMetadata(tmp, parentID); err != nil {
+		return nil, err
+	}
+	if err = os.Rename(tmp, s.layerRoot(l.chainID)); err != nil {
+		return nil, err
+	}
+	l.references = 1
+	s.layers[l.chainID] = l
+	return l, nil
+}
+
+// applyTarStream unpacks ts into a new storage driver layer for l, setting
+// its diff ID and size.
+func (s *Store) applyTarStream(l *Layer, ts io.Reader, root string) error {
+	inflated, err := archive.DecompressStream(ts)
+	if err != nil {
+		return err
+	}
+	defer inflated.Close()
+
+	digester := digest.Canonical.New()
+	if cd, ok := s.driver.(ChainDriver); ok {
+		rdr := io.TeeReader(inflated, digester.Hash())
+		if l.size, err = cd.ApplyDiffChain(l.cacheID, l.parentCacheIDs(), archive.ArchiveReader(rdr)); err != nil {
+			return err
+		}
+		if _, err := io.Copy(ioutil.Discard, rdr); err != nil {
+			return err
+		}
+		l.diffID = DiffID(digester.Digest())
+		return nil
+	}
+
+	if err := s.driver.Create(l.cacheID, l.parentCacheID()); err != nil {
+		return fmt.Errorf("Driver %s failed to create layer %s: %s", s.driver, l.cacheID, err)
+	}
+	return s.disassemble(l, inflated, root, digester, func(rdr io.Reader) (err error) {
+		l.size, err = s.driver.ApplyDiff(l.cacheID, l.parentCacheID(), archive.ArchiveReader(rdr))
+		return err
+	})
+}
+
+// digestTarStream sets the diff ID and size of l, whose storage driver
+// layer already exists, from ts or from the changes of the layer.
+func (s *Store) digestTarStream(l *Layer, ts io.Reader, root string) error {
+	cd, isChain := s.driver.(ChainDriver)
+	if ts == nil {
+		var (
+			a   archive.Archive
+			err error
+		)
+		if isChain {
+			a, err = cd.DiffChain(l.cacheID, l.parentCacheIDs())
+		} else {
+			a, err = s.driver.Diff(l.cacheID, l.parentCacheID())
+		}
+		if err != nil {
+			return err
+		}
+		defer a.Close()
+		ts = a
+	}
+
+	digester := digest.Canonical.New()
+	if isChain {
+		n, err := io.Copy(digester.Hash(), ts)
+		if err != nil {
+			return err
+		}
+		l.size = n
+		l.diffID = DiffID(digester.Digest())
+		return nil
+	}
+	return s.disassemble(l, ts, root, digester, func(rdr io.Reader) (err error) {
+		if _, err := io.Copy(ioutil.Discard, rdr); err != nil {
+			return err
+		}
+		l.size, err = s.driver.DiffSize(l.cacheID, l.parentCacheID())
+		return err
+	})
+}
+
+// disassemble passes the tar stream ts of l to consume while saving its
+// tar-split metadata in root, then sets the diff ID of l from what was
+// read.
+func (s *Store) disassemble(l *Layer, ts io.Reader, root string, digester digest.Digester, consume func(io.Reader) error) error {
+	f, err := os.OpenFile(filepath.Join(root, tarSplitFileName), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fz := gzip.NewWriter(f)
+	defer fz.Close()
+
+	// we're passing nil here for the file putter, because the consumer
+	// handles the content of the files
+	rdr, err := asm.NewInputTarStream(ts, storage.NewJSONPacker(fz), nil)
+	if err != nil {
+		return err
+	}
+	rdr = io.TeeReader(rdr, digester.Hash())
+	if err := consume(rdr); err != nil {
+		return err
+	}
+	// Read the end of the stream the consumer may have left, such as the
+	// padding of the archive, so that the digest covers all of it.
+	if _, err := io.Copy(ioutil.Discard, rdr); err != nil {
+		return err
+	}
+	if err := fz.Close(); err != nil {
+		return err
+	}
+	l.diffID = DiffID(digester.Digest())
+	return nil
+}
+
+func (l *Layer) saveMetadata(root string, parent ChainID) error {
+	files := map[string]string{
+		diffFileName:    l.diffID.String(),
+		sizeFileName:    strconv.FormatInt(l.size, 10),
+		cacheIDFileName: l.cacheID,
+	}
+	if parent != "" {
+		files[parentFileName] = parent.String()
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0600); err != nil {
+			return fmt.Errorf("Error storing %s of layer %s: %v", name, l.cacheID, err)
+		}
+	}
+	return nil
+}
+
+// Get returns the layer chainID without taking a reference to it.
+func (s *Store) Get(chainID ChainID) (*Layer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.layers[chainID]
+	if !ok {
+		return nil, ErrLayerDoesNotExist
+	}
+	return l, nil
+}
+
+// Acquire takes a reference to the layer chainID.
+func (s *Store) Acquire(chainID ChainID) (*Layer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.layers[chainID]
+	if !ok {
+		return nil, ErrLayerDoesNotExist
+	}
+	l.references++
+	return l, nil
+}
+
+// Release drops a reference to l. The layer is removed once it has no
+// reference left, which releases its parent in turn.
+func (s *Store) Release(l *Layer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.releaseLocked(l)
+}
+
+func (s *Store) releaseLocked(l *Layer) error {
+	l.references--
+	if l.references > 0 {
+		return nil
+	}
+	return s.deleteLocked(l)
+}
+
+// Prune removes the layers without any reference.
+func (s *Store) Prune() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var firstErr error
+	for _, l := range s.layers {
+		if l.references > 0 {
+			continue
+		}
+		if err := s.deleteLocked(l); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (s *Store) deleteLocked(l *Layer) error {
+	if _, ok := s.layers[l.chainID]; !ok {
+		return nil
+	}
+	logrus.Debugf("Removing layer %s", l.chainID)
+	delete(s.layers, l.chainID)
+	if err := os.RemoveAll(s.layerRoot(l.chainID)); err != nil {
+		return err
+	}
+	// Ignore the errors of the driver, which may not have the layer
+	// anymore.
+	s.driver.Remove(l.cacheID)
+	if l.parent != nil {
+		return s.releaseLocked(l.parent)
+	}
+	return nil
+}
+
+func (s *Store) layerRoot(chainID ChainID) string {
+	dgst := digest.Digest(chainID)
+	return filepath.Join(s.root, string(dgst.Algorithm()), dgst.Hex())
+}
+
+// assembleTarStream rebuilds the tar stream of l from its tar-split
+// metadata and the content of its storage driver layer.
+func (s *Store) assembleTarStream(l *Layer) (io.ReadCloser, error) {
+	mFileName := filepath.Join(s.layerRoot(l.chainID), tarSplitFileName)
+	mf, err := os.Open(mFileName)
+	if err != nil {
+		return nil, err
+	}
+	pR, pW := io.Pipe()
+	// this will need to be in a goroutine, as we are returning the stream of a
+	// tar archive, but can not close the metadata reader early (when this
+	// function returns)...
+	go func() {
+		defer mf.Close()
+		mfz, err := gzip.NewReader(mf)
+		if err != nil {
+			pW.CloseWithError(fmt.Errorf("[layer] error with %s: %s", mFileName, err))
+			return
+		}
+		defer mfz.Close()
+
+		fsLayer, err := s.driver.Get(l.cacheID, "")
+		if err != nil {
+			pW.CloseWithError(err)
+			return
+		}
+		defer s.driver.Put(l.cacheID)
+
+		ots := asm.NewOutputTarStream(storage.NewPathFileGetter(fsLayer), storage.NewJSONUnpacker(mfz))
+		defer ots.Close()
+		if _, err := io.Copy(pW, ots); err != nil {
+			pW.CloseWithError(err)
+			return
+		}
+		pW.Close()
+	}()
+	return pR, nil
+}
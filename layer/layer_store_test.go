@@ -0,0 +1,209 @@
+package layer
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/daemon/graphdriver/vfs"
+)
+
+func newTestStore(t *testing.T) (*Store, string) {
+	tmp, err := ioutil.TempDir("", "docker-layer-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	driver, err := vfs.Init(filepath.Join(tmp, "vfs"), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewStore(filepath.Join(tmp, "layers"), driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, tmp
+}
+
+func newTestTar(t *testing.T, files map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for name, content := range files {
+		hdr := &tar.Header{
+			Name: name,
+			Mode: 0644,
+			Size: int64(len(content)),
+			Uid:  os.Getuid(),
+			Gid:  os.Getgid(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCreateChainID(t *testing.T) {
+	base := DiffID("sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4")
+	if chainID := CreateChainID("", base); chainID != ChainID(base) {
+		t.Fatalf("Expected the chain ID of a base layer to be its diff ID, got %s", chainID)
+	}
+
+	top := DiffID("sha256:5f70bf18a086007016e948b04aed3b82103a36bea41755b6cddfaf10ace3c6ef")
+	expected, err := digest.FromBytes([]byte(base.String() + " " + top.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chainID := CreateChainIDFromDiffIDs([]DiffID{base, top}); chainID != ChainID(expected) {
+		t.Fatalf("Expected chain ID %s, got %s", expected, chainID)
+	}
+}
+
+func TestRegisterShared(t *testing.T) {
+	s, tmp := newTestStore(t)
+	defer os.RemoveAll(tmp)
+	defer s.driver.Cleanup()
+
+	ts := newTestTar(t, map[string]string{"etc/hostname": "layer\n"})
+	l1, err := s.Register(bytes.NewReader(ts), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dgst, err := digest.FromBytes(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l1.DiffID() != DiffID(dgst) {
+		t.Fatalf("Expected diff ID %s, got %s", dgst, l1.DiffID())
+	}
+	l2, err := s.Register(bytes.NewReader(ts), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l1 != l2 {
+		t.Fatalf("Expected the same content to give the same layer, got %s and %s", l1.CacheID(), l2.CacheID())
+	}
+
+	child, err := s.Register(bytes.NewReader(newTestTar(t, map[string]string{"etc/motd": "child\n"})), l1.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child.Parent() != l1 || child.ChainID() != CreateChainIDFromDiffIDs(child.DiffIDs()) {
+		t.Fatalf("Unexpected child layer %s with diff IDs %v", child.ChainID(), child.DiffIDs())
+	}
+
+	// The layers are restored with the references of their children.
+	s2, err := NewStore(s.root, s.driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := s2.Get(child.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.CacheID() != child.CacheID() || restored.Parent() == nil || restored.Parent().ChainID() != l1.ChainID() {
+		t.Fatalf("Unexpected restored layer %v", restored)
+	}
+
+	// The base layer is held twice and by its child.
+	for i := 0; i < 2; i++ {
+		if err := s.Release(l1); err != nil {
+			t.Fatal(err)
+		}
+		if !s.driver.Exists(l1.CacheID()) {
+			t.Fatalf("Expected layer %s to be kept while it has references", l1.ChainID())
+		}
+	}
+	if err := s.Release(child); err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range []*Layer{child, l1} {
+		if _, err := s.Get(l.ChainID()); err != ErrLayerDoesNotExist {
+			t.Fatalf("Expected layer %s to be removed, got %v", l.ChainID(), err)
+		}
+		if s.driver.Exists(l.CacheID()) {
+			t.Fatalf("Expected the storage of layer %s to be removed", l.ChainID())
+		}
+	}
+}
+
+func TestTarStream(t *testing.T) {
+	s, tmp := newTestStore(t)
+	defer os.RemoveAll(tmp)
+	defer s.driver.Cleanup()
+
+	ts := newTestTar(t, map[string]string{"etc/hostname": "layer\n"})
+	l, err := s.Register(bytes.NewReader(ts), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := l.TarStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, ts) {
+		t.Fatal("Expected the tar stream of the layer to be the one it was registered with")
+	}
+
+	// Tamper with the content of the layer.
+	dir, err := s.driver.Get(l.CacheID(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "etc", "hostname"), []byte("LAYER\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s.driver.Put(l.CacheID())
+	if rc, err = l.TarStream(); err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if _, err := ioutil.ReadAll(rc); err == nil {
+		t.Fatal("Expected an error reading a layer whose content does not match its diff ID")
+	}
+}
+
+func TestRegisterExisting(t *testing.T) {
+	s, tmp := newTestStore(t)
+	defer os.RemoveAll(tmp)
+	defer s.driver.Cleanup()
+
+	// A layer created by the storage driver alone, as the ones of the
+	// legacy graph.
+	if err := s.driver.Create("legacy", ""); err != nil {
+		t.Fatal(err)
+	}
+	ts := newTestTar(t, map[string]string{"etc/hostname": "legacy\n"})
+	if _, err := s.driver.ApplyDiff("legacy", "", bytes.NewReader(ts)); err != nil {
+		t.Fatal(err)
+	}
+	l, err := s.RegisterExisting("legacy", "", bytes.NewReader(ts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.CacheID() != "legacy" || l.Size() != int64(len("legacy\n")) {
+		t.Fatalf("Unexpected layer %s with size %d", l.CacheID(), l.Size())
+	}
+	// The same content registered again gives the existing layer.
+	l2, err := s.Register(bytes.NewReader(ts), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l2 != l {
+		t.Fatalf("Expected layer %s, got %s", l.ChainID(), l2.ChainID())
+	}
+}
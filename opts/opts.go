@@ -6,9 +6,12 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/docker/docker/pkg/blkiodev"
 	"github.com/docker/docker/pkg/parsers"
+	"github.com/docker/docker/pkg/units"
 	"github.com/docker/docker/volume"
 )
 
@@ -225,6 +228,49 @@ func validatePath(val string, validateMountMode bool) (string, error) {
 	return val, nil
 }
 
+// ValidateThrottleBpsDevice Validates a throttle device option for bytes per second
+// It will make sure 'val' is in the form:
+//    device-path:rate
+// where the rate is a human readable size such as 10mb.
+func ValidateThrottleBpsDevice(val string) (*blkiodev.ThrottleDevice, error) {
+	path, rate, err := splitThrottleDevice(val)
+	if err != nil {
+		return nil, err
+	}
+	bps, err := units.RAMInBytes(rate)
+	if err != nil || bps < 0 {
+		return nil, fmt.Errorf("invalid rate for device: %s. The correct format is <device-path>:<number>[<unit>]. Number must be a positive integer. Unit is optional and can be kb, mb, or gb", val)
+	}
+	return &blkiodev.ThrottleDevice{Path: path, Rate: uint64(bps)}, nil
+}
+
+// ValidateThrottleIOpsDevice Validates a throttle device option for IO per second
+// It will make sure 'val' is in the form:
+//    device-path:rate
+func ValidateThrottleIOpsDevice(val string) (*blkiodev.ThrottleDevice, error) {
+	path, rate, err := splitThrottleDevice(val)
+	if err != nil {
+		return nil, err
+	}
+	iops, err := strconv.ParseUint(rate, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate for device: %s. The correct format is <device-path>:<number>. Number must be a positive integer", val)
+	}
+	return &blkiodev.ThrottleDevice{Path: path, Rate: iops}, nil
+}
+
+func splitThrottleDevice(val string) (string, string, error) {
+	i := strings.LastIndex(val, ":")
+	if i < 0 {
+		return "", "", fmt.Errorf("bad format: %s", val)
+	}
+	path, rate := val[:i], val[i+1:]
+	if !strings.HasPrefix(path, "/dev/") {
+		return "", "", fmt.Errorf("bad format for device path: %s", val)
+	}
+	return path, rate, nil
+}
+
 // ValidateEnv Validate an environment variable and returns it
 // It will use EnvironmentVariableRegexp to ensure the name of the environment variable is valid.
 // If no value is specified, it returns the current value using os.Getenv.
@@ -436,12 +436,12 @@ func TestValidateHost(t *testing.T) {
 	invalid := map[string]string{
 		"anything":              "Invalid bind address format: anything",
 		"something with spaces": "Invalid bind address format: something with spaces",
-		"://":                "Invalid bind address format: ://",
-		"unknown://":         "Invalid bind address format: unknown://",
-		"tcp://":             "Invalid proto, expected tcp: ",
-		"tcp://:port":        "Invalid bind address format: :port",
-		"tcp://invalid":      "Invalid bind address format: invalid",
-		"tcp://invalid:port": "Invalid bind address format: invalid:port",
+		"://":                   "Invalid bind address format: ://",
+		"unknown://":            "Invalid bind address format: unknown://",
+		"tcp://":                "Invalid proto, expected tcp: ",
+		"tcp://:port":           "Invalid bind address format: :port",
+		"tcp://invalid":         "Invalid bind address format: invalid",
+		"tcp://invalid:port":    "Invalid bind address format: invalid:port",
 	}
 	valid := map[string]string{
 		"fd://":                    "fd://",
@@ -477,3 +477,41 @@ func logOptsValidator(val string) (string, error) {
 	}
 	return "", fmt.Errorf("invalid key %s", vals[0])
 }
+
+func TestValidateThrottleBpsDevice(t *testing.T) {
+	valid := map[string]uint64{
+		"/dev/sda:10mb":   10 * 1024 * 1024,
+		"/dev/sda:1024":   1024,
+		"/dev/disk/a:1KB": 1024,
+	}
+	for val, rate := range valid {
+		d, err := ValidateThrottleBpsDevice(val)
+		if err != nil {
+			t.Fatalf("ValidateThrottleBpsDevice(`%s`) should succeed: %v", val, err)
+		}
+		if d.Rate != rate || !strings.HasPrefix(val, d.Path+":") {
+			t.Fatalf("ValidateThrottleBpsDevice(`%s`) got %v", val, d)
+		}
+	}
+
+	invalid := []string{"/dev/sda", "sda:10mb", "/dev/sda:", "/dev/sda:-1", "/dev/sda:10tons"}
+	for _, val := range invalid {
+		if _, err := ValidateThrottleBpsDevice(val); err == nil {
+			t.Fatalf("ValidateThrottleBpsDevice(`%s`) should have failed validation", val)
+		}
+	}
+}
+
+func TestValidateThrottleIOpsDevice(t *testing.T) {
+	d, err := ValidateThrottleIOpsDevice("/dev/sda:1000")
+	if err != nil || d.Path != "/dev/sda" || d.Rate != 1000 {
+		t.Fatalf("ValidateThrottleIOpsDevice(`/dev/sda:1000`) got %v %v", d, err)
+	}
+
+	invalid := []string{"/dev/sda", "sda:1000", "/dev/sda:10mb", "/dev/sda:-1"}
+	for _, val := range invalid {
+		if _, err := ValidateThrottleIOpsDevice(val); err == nil {
+			t.Fatalf("ValidateThrottleIOpsDevice(`%s`) should have failed validation", val)
+		}
+	}
+}
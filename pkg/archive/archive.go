@@ -19,6 +19,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/pkg/fileutils"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/pools"
 	"github.com/docker/docker/pkg/promise"
 	"github.com/docker/docker/pkg/system"
@@ -44,12 +45,22 @@ type (
 		// For each include when creating an archive, the included name will be
 		// replaced with the matching name from this map.
 		RebaseNames map[string]string
+		// The user and group ID mappings of the containers the files
+		// belong to. When creating an archive, the ownership of the files
+		// is translated to the one in the containers; when unpacking, it
+		// is translated back to the host IDs.
+		UIDMaps []idtools.IDMap
+		GIDMaps []idtools.IDMap
 	}
 
 	// Archiver allows the reuse of most utility functions of this package
-	// with a pluggable Untar function.
+	// with a pluggable Untar function. The ownership of the copied files is
+	// taken as the one in the containers with the given ID mappings, and is
+	// translated to the host IDs in the destination.
 	Archiver struct {
-		Untar func(io.Reader, string, *TarOptions) error
+		Untar   func(io.Reader, string, *TarOptions) error
+		UIDMaps []idtools.IDMap
+		GIDMaps []idtools.IDMap
 	}
 
 	// breakoutError is used to differentiate errors related to breaking out
@@ -60,7 +71,7 @@ type (
 
 var (
 	ErrNotImplemented = errors.New("Function not implemented")
-	defaultArchiver   = &Archiver{Untar}
+	defaultArchiver   = &Archiver{Untar: Untar}
 )
 
 const (
@@ -179,6 +190,10 @@ type tarAppender struct {
 
 	// for hardlink mapping
 	SeenFiles map[uint64]string
+
+	// the ID mappings the ownership of the files is translated with
+	UIDMaps []idtools.IDMap
+	GIDMaps []idtools.IDMap
 }
 
 // canonicalTarName provides a platform-independent and consistent posix-style
@@ -226,6 +241,10 @@ func (ta *tarAppender) addTarFile(path, name string) error {
 		return err
 	}
 
+	if err := remapIDsToContainer(hdr, ta.UIDMaps, ta.GIDMaps); err != nil {
+		return err
+	}
+
 	// if it's a regular file and has more than 1 link,
 	// it's hardlinked, so set the type flag accordingly
 	if fi.Mode().IsRegular() && nlink > 1 {
@@ -272,6 +291,36 @@ func (ta *tarAppender) addTarFile(path, name string) error {
 	return nil
 }
 
+// remapIDsToContainer translates the ownership in hdr from host IDs to
+// container IDs.
+func remapIDsToContainer(hdr *tar.Header, uidMaps, gidMaps []idtools.IDMap) error {
+	uid, err := idtools.ToContainer(hdr.Uid, uidMaps)
+	if err != nil {
+		return err
+	}
+	gid, err := idtools.ToContainer(hdr.Gid, gidMaps)
+	if err != nil {
+		return err
+	}
+	hdr.Uid, hdr.Gid = uid, gid
+	return nil
+}
+
+// remapIDsToHost translates the ownership in hdr from container IDs to
+// host IDs.
+func remapIDsToHost(hdr *tar.Header, uidMaps, gidMaps []idtools.IDMap) error {
+	uid, err := idtools.ToHost(hdr.Uid, uidMaps)
+	if err != nil {
+		return err
+	}
+	gid, err := idtools.ToHost(hdr.Gid, gidMaps)
+	if err != nil {
+		return err
+	}
+	hdr.Uid, hdr.Gid = uid, gid
+	return nil
+}
+
 func createTarFile(path, extractDir string, hdr *tar.Header, reader io.Reader, Lchown bool, chownOpts *TarChownOptions) error {
 	// hdr.Mode is in linux format, which we can use for sycalls,
 	// but for os.Foo() calls we need the mode converted to os.FileMode,
@@ -408,6 +457,8 @@ func TarWithOptions(srcPath string, options *TarOptions) (io.ReadCloser, error)
 			TarWriter: tar.NewWriter(compressWriter),
 			Buffer:    pools.BufioWriter32KPool.Get(nil),
 			SeenFiles: make(map[uint64]string),
+			UIDMaps:   options.UIDMaps,
+			GIDMaps:   options.GIDMaps,
 		}
 
 		defer func() {
@@ -536,6 +587,10 @@ func Unpack(decompressedArchive io.Reader, dest string, options *TarOptions) err
 	defer pools.BufioReader32KPool.Put(trBuf)
 
 	var dirs []*tar.Header
+	rootUID, rootGID, err := idtools.GetRootUIDGID(options.UIDMaps, options.GIDMaps)
+	if err != nil {
+		return err
+	}
 
 	// Iterate through the files in the archive.
 loop:
@@ -568,7 +623,7 @@ loop:
 			parent := filepath.Dir(hdr.Name)
 			parentPath := filepath.Join(dest, parent)
 			if _, err := os.Lstat(parentPath); err != nil && os.IsNotExist(err) {
-				err = system.MkdirAll(parentPath, 0777)
+				err = idtools.MkdirAllAs(parentPath, 0777, rootUID, rootGID)
 				if err != nil {
 					return err
 				}
@@ -613,6 +668,12 @@ loop:
 		}
 		trBuf.Reset(tr)
 
+		if options.ChownOpts == nil {
+			if err := remapIDsToHost(hdr, options.UIDMaps, options.GIDMaps); err != nil {
+				return err
+			}
+		}
+
 		if err := createTarFile(path, dest, hdr, trBuf, !options.NoLchown, options.ChownOpts); err != nil {
 			return err
 		}
@@ -683,7 +744,7 @@ func (archiver *Archiver) TarUntar(src, dst string) error {
 		return err
 	}
 	defer archive.Close()
-	return archiver.Untar(archive, dst, nil)
+	return archiver.Untar(archive, dst, &TarOptions{UIDMaps: archiver.UIDMaps, GIDMaps: archiver.GIDMaps})
 }
 
 // TarUntar is a convenience function which calls Tar and Untar, with the output of one piped into the other.
@@ -698,7 +759,7 @@ func (archiver *Archiver) UntarPath(src, dst string) error {
 		return err
 	}
 	defer archive.Close()
-	if err := archiver.Untar(archive, dst, nil); err != nil {
+	if err := archiver.Untar(archive, dst, &TarOptions{UIDMaps: archiver.UIDMaps, GIDMaps: archiver.GIDMaps}); err != nil {
 		return err
 	}
 	return nil
@@ -720,7 +781,11 @@ func (archiver *Archiver) CopyWithTar(src, dst string) error {
 	}
 	// Create dst, copy src's content into it
 	logrus.Debugf("Creating dest directory: %s", dst)
-	if err := system.MkdirAll(dst, 0755); err != nil {
+	rootUID, rootGID, err := idtools.GetRootUIDGID(archiver.UIDMaps, archiver.GIDMaps)
+	if err != nil {
+		return err
+	}
+	if err := idtools.MkdirAllAs(dst, 0755, rootUID, rootGID); err != nil {
 		return err
 	}
 	logrus.Debugf("Calling TarUntar(%s, %s)", src, dst)
@@ -752,7 +817,11 @@ func (archiver *Archiver) CopyFileWithTar(src, dst string) (err error) {
 		dst = filepath.Join(dst, filepath.Base(src))
 	}
 	// Create the holding directory if necessary
-	if err := system.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+	rootUID, rootGID, err := idtools.GetRootUIDGID(archiver.UIDMaps, archiver.GIDMaps)
+	if err != nil {
+		return err
+	}
+	if err := idtools.MkdirAllAs(filepath.Dir(dst), 0700, rootUID, rootGID); err != nil {
 		return err
 	}
 
@@ -788,7 +857,7 @@ func (archiver *Archiver) CopyFileWithTar(src, dst string) (err error) {
 			err = er
 		}
 	}()
-	return archiver.Untar(r, filepath.Dir(dst), nil)
+	return archiver.Untar(r, filepath.Dir(dst), &TarOptions{UIDMaps: archiver.UIDMaps, GIDMaps: archiver.GIDMaps})
 }
 
 // CopyFileWithTar emulates the behavior of the 'cp' command-line
@@ -3,8 +3,15 @@
 package archive
 
 import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
+
+	"github.com/docker/docker/pkg/idtools"
 )
 
 func TestCanonicalTarNameForPath(t *testing.T) {
@@ -58,3 +65,78 @@ func TestChmodTarEntry(t *testing.T) {
 		}
 	}
 }
+
+func TestRemapIDs(t *testing.T) {
+	uidMaps := []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 1000}}
+	gidMaps := []idtools.IDMap{{ContainerID: 0, HostID: 200000, Size: 1000}}
+
+	hdr := &tar.Header{Uid: 10, Gid: 20}
+	if err := remapIDsToHost(hdr, uidMaps, gidMaps); err != nil {
+		t.Fatal(err)
+	}
+	if hdr.Uid != 100010 || hdr.Gid != 200020 {
+		t.Fatalf("wrong host IDs. expected:100010:200020 got:%d:%d", hdr.Uid, hdr.Gid)
+	}
+	if err := remapIDsToContainer(hdr, uidMaps, gidMaps); err != nil {
+		t.Fatal(err)
+	}
+	if hdr.Uid != 10 || hdr.Gid != 20 {
+		t.Fatalf("wrong container IDs. expected:10:20 got:%d:%d", hdr.Uid, hdr.Gid)
+	}
+
+	if err := remapIDsToHost(&tar.Header{Uid: 1000}, uidMaps, gidMaps); err == nil {
+		t.Fatal("expected an error remapping an ID out of the mappings")
+	}
+	if err := remapIDsToContainer(&tar.Header{Uid: 0}, uidMaps, gidMaps); err == nil {
+		t.Fatal("expected an error remapping an ID out of the mappings")
+	}
+}
+
+func TestUntarWithIDMaps(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("chowning to the remapped IDs requires root")
+	}
+	tmpDir, err := ioutil.TempDir("", "docker-test-untar-idmaps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, hdr := range []*tar.Header{
+		{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "dir/file", Typeflag: tar.TypeReg, Mode: 0644, Uid: 1, Gid: 2},
+	} {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	options := &TarOptions{
+		UIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 1000}},
+		GIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 1000}},
+	}
+	dest := filepath.Join(tmpDir, "dest")
+	if err := Untar(buf, dest, options); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, expected := range map[string][2]uint32{
+		dest:                            {100000, 100000},
+		filepath.Join(dest, "dir"):      {100000, 100000},
+		filepath.Join(dest, "dir/file"): {100001, 100002},
+	} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st := fi.Sys().(*syscall.Stat_t)
+		if st.Uid != expected[0] || st.Gid != expected[1] {
+			t.Fatalf("wrong owner of %s. expected:%d:%d got:%d:%d", path, expected[0], expected[1], st.Uid, st.Gid)
+		}
+	}
+}
@@ -90,6 +90,30 @@ func TarResource(sourceInfo CopyInfo) (content Archive, err error) {
 // TarResourceRebase is like TarResource but renames the first path element of
 // items in the resulting tar archive to match the given rebaseName if not "".
 func TarResourceRebase(sourcePath, rebaseName string) (content Archive, err error) {
+	return TarResourceRebaseWithOptions(sourcePath, TarResourceRebaseOpts(sourcePath, rebaseName))
+}
+
+// TarResourceRebaseOpts returns the TarOptions TarResourceRebase archives
+// sourcePath with, for callers which need to set further options.
+func TarResourceRebaseOpts(sourcePath, rebaseName string) *TarOptions {
+	// Separate the source path between it's directory and
+	// the entry in that directory which we are archiving.
+	_, sourceBase := SplitPathDirEntry(sourcePath)
+
+	filter := []string{sourceBase}
+	return &TarOptions{
+		Compression:      Uncompressed,
+		IncludeFiles:     filter,
+		IncludeSourceDir: true,
+		RebaseNames: map[string]string{
+			sourceBase: rebaseName,
+		},
+	}
+}
+
+// TarResourceRebaseWithOptions is like TarResourceRebase with the given
+// options, as returned by TarResourceRebaseOpts.
+func TarResourceRebaseWithOptions(sourcePath string, options *TarOptions) (content Archive, err error) {
 	if _, err = os.Lstat(sourcePath); err != nil {
 		// Catches the case where the source does not exist or is not a
 		// directory if asserted to be a directory, as this also causes an
@@ -101,18 +125,9 @@ func TarResourceRebase(sourcePath, rebaseName string) (content Archive, err erro
 	// the entry in that directory which we are archiving.
 	sourceDir, sourceBase := SplitPathDirEntry(sourcePath)
 
-	filter := []string{sourceBase}
-
 	logrus.Debugf("copying %q from %q", sourceBase, sourceDir)
 
-	return TarWithOptions(sourceDir, &TarOptions{
-		Compression:      Uncompressed,
-		IncludeFiles:     filter,
-		IncludeSourceDir: true,
-		RebaseNames: map[string]string{
-			sourceBase: rebaseName,
-		},
-	})
+	return TarWithOptions(sourceDir, options)
 }
 
 // CopyInfo holds basic info about the source
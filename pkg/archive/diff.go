@@ -12,16 +12,27 @@ import (
 	"syscall"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/pools"
 	"github.com/docker/docker/pkg/system"
 )
 
-func UnpackLayer(dest string, layer ArchiveReader) (size int64, err error) {
+// UnpackLayer unpacks the uncompressed tar stream `layer` to `dest`,
+// translating the ownership of the files with the ID mappings of options,
+// which may be nil.
+func UnpackLayer(dest string, layer ArchiveReader, options *TarOptions) (size int64, err error) {
 	tr := tar.NewReader(layer)
 	trBuf := pools.BufioReader32KPool.Get(tr)
 	defer pools.BufioReader32KPool.Put(trBuf)
 
+	if options == nil {
+		options = &TarOptions{}
+	}
 	var dirs []*tar.Header
+	rootUID, rootGID, err := idtools.GetRootUIDGID(options.UIDMaps, options.GIDMaps)
+	if err != nil {
+		return 0, err
+	}
 
 	aufsTempdir := ""
 	aufsHardlinks := make(map[string]*tar.Header)
@@ -72,13 +83,17 @@ func UnpackLayer(dest string, layer ArchiveReader) (size int64, err error) {
 			parentPath := filepath.Join(dest, parent)
 
 			if _, err := os.Lstat(parentPath); err != nil && os.IsNotExist(err) {
-				err = system.MkdirAll(parentPath, 0600)
+				err = idtools.MkdirAllAs(parentPath, 0600, rootUID, rootGID)
 				if err != nil {
 					return 0, err
 				}
 			}
 		}
 
+		if err := remapIDsToHost(hdr, options.UIDMaps, options.GIDMaps); err != nil {
+			return 0, err
+		}
+
 		// Skip AUFS metadata dirs
 		if strings.HasPrefix(hdr.Name, ".wh..wh.") {
 			// Regular files inside /.wh..wh.plnk can be used as hardlink targets
@@ -178,19 +193,20 @@ func UnpackLayer(dest string, layer ArchiveReader) (size int64, err error) {
 // compressed or uncompressed.
 // Returns the size in bytes of the contents of the layer.
 func ApplyLayer(dest string, layer ArchiveReader) (int64, error) {
-	return applyLayerHandler(dest, layer, true)
+	return applyLayerHandler(dest, layer, nil, true)
 }
 
 // ApplyUncompressedLayer parses a diff in the standard layer format from
 // `layer`, and applies it to the directory `dest`. The stream `layer`
-// can only be uncompressed.
+// can only be uncompressed. The ownership of the files is translated with the
+// ID mappings of options, which may be nil.
 // Returns the size in bytes of the contents of the layer.
-func ApplyUncompressedLayer(dest string, layer ArchiveReader) (int64, error) {
-	return applyLayerHandler(dest, layer, false)
+func ApplyUncompressedLayer(dest string, layer ArchiveReader, options *TarOptions) (int64, error) {
+	return applyLayerHandler(dest, layer, options, false)
 }
 
 // do the bulk load of ApplyLayer, but allow for not calling DecompressStream
-func applyLayerHandler(dest string, layer ArchiveReader, decompress bool) (int64, error) {
+func applyLayerHandler(dest string, layer ArchiveReader, options *TarOptions, decompress bool) (int64, error) {
 	dest = filepath.Clean(dest)
 
 	// We need to be able to set any perms
@@ -206,5 +222,5 @@ func applyLayerHandler(dest string, layer ArchiveReader, decompress bool) (int64
 			return 0, err
 		}
 	}
-	return UnpackLayer(dest, layer)
+	return UnpackLayer(dest, layer, options)
 }
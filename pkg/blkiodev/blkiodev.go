@@ -0,0 +1,15 @@
+// Package blkiodev provides the structure used to represent per-device
+// block IO limits.
+package blkiodev
+
+import "fmt"
+
+// ThrottleDevice is a structure that holds device:rate_per_second pair
+type ThrottleDevice struct {
+	Path string
+	Rate uint64
+}
+
+func (t *ThrottleDevice) String() string {
+	return fmt.Sprintf("%s:%d", t.Path, t.Rate)
+}
@@ -17,6 +17,10 @@ import (
 	"github.com/docker/docker/pkg/system"
 )
 
+// applyLayerOptionsEnv is the environment variable the options are passed
+// to docker-applyLayer with.
+const applyLayerOptionsEnv = "DOCKER_APPLYLAYER_OPTIONS"
+
 type applyLayerResponse struct {
 	LayerSize int64 `json:"layerSize"`
 }
@@ -48,8 +52,13 @@ func applyLayer() {
 		fatal(err)
 	}
 
+	var options *archive.TarOptions
+	if err := json.Unmarshal([]byte(os.Getenv(applyLayerOptionsEnv)), &options); err != nil {
+		fatal(err)
+	}
+
 	os.Setenv("TMPDIR", tmpDir)
-	size, err := archive.UnpackLayer("/", os.Stdin)
+	size, err := archive.UnpackLayer("/", os.Stdin, options)
 	os.RemoveAll(tmpDir)
 	if err != nil {
 		fatal(err)
@@ -68,7 +77,7 @@ func applyLayer() {
 // applyLayerHandler parses a diff in the standard layer format from `layer`, and
 // applies it to the directory `dest`. Returns the size in bytes of the
 // contents of the layer.
-func applyLayerHandler(dest string, layer archive.ArchiveReader, decompress bool) (size int64, err error) {
+func applyLayerHandler(dest string, layer archive.ArchiveReader, options *archive.TarOptions, decompress bool) (size int64, err error) {
 	dest = filepath.Clean(dest)
 	if decompress {
 		decompressed, err := archive.DecompressStream(layer)
@@ -80,8 +89,17 @@ func applyLayerHandler(dest string, layer archive.ArchiveReader, decompress bool
 		layer = decompressed
 	}
 
+	if options == nil {
+		options = &archive.TarOptions{}
+	}
+	opts, err := json.Marshal(options)
+	if err != nil {
+		return 0, fmt.Errorf("ApplyLayer json encode: %v", err)
+	}
+
 	cmd := reexec.Command("docker-applyLayer", dest)
 	cmd.Stdin = layer
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", applyLayerOptionsEnv, opts))
 
 	outBuf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
 	cmd.Stdout, cmd.Stderr = outBuf, errBuf
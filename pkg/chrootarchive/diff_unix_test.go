@@ -0,0 +1,58 @@
+// +build !windows
+
+package chrootarchive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/system"
+)
+
+func TestChrootApplyUncompressedLayerWithIDMaps(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("chowning to the remapped IDs requires root")
+	}
+	tmpdir, err := ioutil.TempDir("", "docker-TestChrootApplyUncompressedLayerWithIDMaps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	src := filepath.Join(tmpdir, "src")
+	if err := system.MkdirAll(filepath.Join(src, "dir"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "dir", "file"), []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := archive.Tar(src, archive.Uncompressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(tmpdir, "dest")
+	if err := system.MkdirAll(dest, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	options := &archive.TarOptions{
+		UIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 1000}},
+		GIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 200000, Size: 1000}},
+	}
+	if _, err := ApplyUncompressedLayer(dest, stream, options); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(filepath.Join(dest, "dir", "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := fi.Sys().(*syscall.Stat_t)
+	if st.Uid != 100000 || st.Gid != 200000 {
+		t.Fatalf("wrong owner. expected:100000:200000 got:%d:%d", st.Uid, st.Gid)
+	}
+}
@@ -0,0 +1,187 @@
+// Package idtools provides the helpers to map user and group IDs between
+// the host and containers running in a user namespace, with the ID ranges
+// read from the subordinate ID files, /etc/subuid and /etc/subgid.
+package idtools
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IDMap contains a single entry for user namespace range remapping. A list
+// of IDMap entries is the mapping provided to the kernel when a user
+// namespace is created.
+type IDMap struct {
+	ContainerID int `json:"container_id"`
+	HostID      int `json:"host_id"`
+	Size        int `json:"size"`
+}
+
+type subIDRange struct {
+	Start  int
+	Length int
+}
+
+type subIDRanges []subIDRange
+
+func (e subIDRanges) Len() int           { return len(e) }
+func (e subIDRanges) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e subIDRanges) Less(i, j int) bool { return e[i].Start < e[j].Start }
+
+const (
+	subuidFileName = "/etc/subuid"
+	subgidFileName = "/etc/subgid"
+)
+
+// MkdirAllAs creates a directory, along with any missing parents, owned by
+// ownerUID and ownerGID. The ownership of the directories which already
+// exist is unchanged, and so is the one of the new directories when the
+// process isn't running as root.
+func MkdirAllAs(path string, mode os.FileMode, ownerUID, ownerGID int) error {
+	return mkdirAs(path, mode, ownerUID, ownerGID, true)
+}
+
+// MkdirAs creates a directory owned by ownerUID and ownerGID. The parent
+// directory must exist. If the directory already exists, its ownership is
+// unchanged.
+func MkdirAs(path string, mode os.FileMode, ownerUID, ownerGID int) error {
+	return mkdirAs(path, mode, ownerUID, ownerGID, false)
+}
+
+// GetRootUIDGID returns the host IDs of the root user and group of the
+// containers with the given mappings. Without mappings, the root of the
+// containers is the root of the host.
+func GetRootUIDGID(uidMap, gidMap []IDMap) (int, int, error) {
+	var uid, gid int
+	if uidMap != nil {
+		id, err := ToHost(0, uidMap)
+		if err != nil {
+			return -1, -1, err
+		}
+		uid = id
+	}
+	if gidMap != nil {
+		id, err := ToHost(0, gidMap)
+		if err != nil {
+			return -1, -1, err
+		}
+		gid = id
+	}
+	return uid, gid, nil
+}
+
+// ToContainer translates a host ID to the ID it has in the containers with
+// the given mapping. Without mapping, the IDs are the same.
+func ToContainer(hostID int, idMap []IDMap) (int, error) {
+	if idMap == nil {
+		return hostID, nil
+	}
+	for _, m := range idMap {
+		if hostID >= m.HostID && hostID < m.HostID+m.Size {
+			return m.ContainerID + (hostID - m.HostID), nil
+		}
+	}
+	return -1, fmt.Errorf("Host ID %d cannot be mapped to a container ID", hostID)
+}
+
+// ToHost translates a container ID to the ID it has on the host with the
+// given mapping. Without mapping, the IDs are the same.
+func ToHost(contID int, idMap []IDMap) (int, error) {
+	if idMap == nil {
+		return contID, nil
+	}
+	for _, m := range idMap {
+		if contID >= m.ContainerID && contID < m.ContainerID+m.Size {
+			return m.HostID + (contID - m.ContainerID), nil
+		}
+	}
+	return -1, fmt.Errorf("Container ID %d cannot be mapped to a host ID", contID)
+}
+
+// CreateIDMappings returns the user and group ID mappings for the
+// subordinate ID ranges of username in /etc/subuid and of groupname in
+// /etc/subgid.
+func CreateIDMappings(username, groupname string) ([]IDMap, []IDMap, error) {
+	subuidRanges, err := parseSubuid(username)
+	if err != nil {
+		return nil, nil, err
+	}
+	subgidRanges, err := parseSubgid(groupname)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(subuidRanges) == 0 {
+		return nil, nil, fmt.Errorf("No subuid ranges found for user %q in %s", username, subuidFileName)
+	}
+	if len(subgidRanges) == 0 {
+		return nil, nil, fmt.Errorf("No subgid ranges found for group %q in %s", groupname, subgidFileName)
+	}
+
+	return createIDMap(subuidRanges), createIDMap(subgidRanges), nil
+}
+
+// createIDMap maps the ranges, in the order of their start, to contiguous
+// container IDs starting at 0.
+func createIDMap(subidRanges subIDRanges) []IDMap {
+	sort.Sort(subidRanges)
+	idMap := []IDMap{}
+	containerID := 0
+	for _, idrange := range subidRanges {
+		idMap = append(idMap, IDMap{
+			ContainerID: containerID,
+			HostID:      idrange.Start,
+			Size:        idrange.Length,
+		})
+		containerID = containerID + idrange.Length
+	}
+	return idMap
+}
+
+func parseSubuid(username string) (subIDRanges, error) {
+	return parseSubidFile(subuidFileName, username)
+}
+
+func parseSubgid(username string) (subIDRanges, error) {
+	return parseSubidFile(subgidFileName, username)
+}
+
+// parseSubidFile returns the ranges of username in a subordinate ID file.
+// Each line of the file has the format name:start:length.
+func parseSubidFile(path, username string) (subIDRanges, error) {
+	var rangeList subIDRanges
+
+	subidFile, err := os.Open(path)
+	if err != nil {
+		return rangeList, err
+	}
+	defer subidFile.Close()
+
+	s := bufio.NewScanner(subidFile)
+	for s.Scan() {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		parts := strings.Split(text, ":")
+		if len(parts) != 3 {
+			return rangeList, fmt.Errorf("Cannot parse subuid/gid information: Format not correct for %s file", path)
+		}
+		if parts[0] != username {
+			continue
+		}
+		startid, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return rangeList, fmt.Errorf("String to int conversion failed during subuid/gid parsing of %s: %v", path, err)
+		}
+		length, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return rangeList, fmt.Errorf("String to int conversion failed during subuid/gid parsing of %s: %v", path, err)
+		}
+		rangeList = append(rangeList, subIDRange{startid, length})
+	}
+	return rangeList, s.Err()
+}
@@ -0,0 +1,92 @@
+package idtools
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseSubidFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "subuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(`# comment
+dockremap:200000:1000
+other:100000:65536
+
+dockremap:100000:65536
+`)
+	f.Close()
+
+	ranges, err := parseSubidFile(f.Name(), "dockremap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := subIDRanges{{200000, 1000}, {100000, 65536}}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Fatalf("Expected %v, got %v", expected, ranges)
+	}
+
+	idMap := createIDMap(ranges)
+	expectedMap := []IDMap{
+		{ContainerID: 0, HostID: 100000, Size: 65536},
+		{ContainerID: 65536, HostID: 200000, Size: 1000},
+	}
+	if !reflect.DeepEqual(idMap, expectedMap) {
+		t.Fatalf("Expected %v, got %v", expectedMap, idMap)
+	}
+
+	if ranges, err := parseSubidFile(f.Name(), "nobody"); err != nil || len(ranges) != 0 {
+		t.Fatalf("Expected no range for an unknown user, got %v, %v", ranges, err)
+	}
+}
+
+func TestParseSubidFileInvalid(t *testing.T) {
+	f, err := ioutil.TempFile("", "subuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("dockremap:100000\n")
+	f.Close()
+
+	if _, err := parseSubidFile(f.Name(), "dockremap"); err == nil {
+		t.Fatal("Expected an error for an invalid line")
+	}
+}
+
+func TestToHostToContainer(t *testing.T) {
+	idMap := []IDMap{
+		{ContainerID: 0, HostID: 100000, Size: 1000},
+		{ContainerID: 1000, HostID: 300000, Size: 1000},
+	}
+
+	for _, c := range []struct{ cont, host int }{{0, 100000}, {999, 100999}, {1000, 300000}, {1500, 300500}} {
+		host, err := ToHost(c.cont, idMap)
+		if err != nil || host != c.host {
+			t.Fatalf("Expected container ID %d to map to %d, got %d, %v", c.cont, c.host, host, err)
+		}
+		cont, err := ToContainer(c.host, idMap)
+		if err != nil || cont != c.cont {
+			t.Fatalf("Expected host ID %d to map to %d, got %d, %v", c.host, c.cont, cont, err)
+		}
+	}
+
+	if _, err := ToHost(2000, idMap); err == nil {
+		t.Fatal("Expected an error for an unmapped container ID")
+	}
+	if _, err := ToContainer(0, idMap); err == nil {
+		t.Fatal("Expected an error for an unmapped host ID")
+	}
+	if id, err := ToHost(42, nil); err != nil || id != 42 {
+		t.Fatalf("Expected IDs to be unchanged without mapping, got %d, %v", id, err)
+	}
+
+	uid, gid, err := GetRootUIDGID(idMap, []IDMap{{ContainerID: 0, HostID: 200000, Size: 10}})
+	if err != nil || uid != 100000 || gid != 200000 {
+		t.Fatalf("Expected root to be 100000:200000, got %d:%d, %v", uid, gid, err)
+	}
+}
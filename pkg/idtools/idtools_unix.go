@@ -0,0 +1,46 @@
+// +build !windows
+
+package idtools
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/pkg/system"
+)
+
+func mkdirAs(path string, mode os.FileMode, ownerUID, ownerGID int, mkAll bool) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	// the directories to chown: path and the parents created along with it
+	paths := []string{path}
+	if mkAll {
+		for dir := filepath.Dir(path); dir != "/" && dir != "."; dir = filepath.Dir(dir) {
+			if _, err := os.Stat(dir); !os.IsNotExist(err) {
+				break
+			}
+			paths = append(paths, dir)
+		}
+		if err := system.MkdirAll(path, mode); err != nil && !os.IsExist(err) {
+			return err
+		}
+	} else {
+		if err := os.Mkdir(path, mode); err != nil && !os.IsExist(err) {
+			return err
+		}
+	}
+
+	// only root can give the directories away; other users, like the
+	// client extracting an archive, keep them
+	if os.Geteuid() != 0 {
+		return nil
+	}
+	for _, p := range paths {
+		if err := os.Chown(p, ownerUID, ownerGID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
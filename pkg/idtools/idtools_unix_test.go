@@ -0,0 +1,53 @@
+// +build !windows
+
+package idtools
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func statOwner(t *testing.T, path string) (int, int) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := fi.Sys().(*syscall.Stat_t)
+	return int(st.Uid), int(st.Gid)
+}
+
+func TestMkdirAllAs(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("chown requires root")
+	}
+	dir, err := ioutil.TempDir("", "mkdirallas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "usr", "share")
+	if err := MkdirAllAs(path, 0755, 99, 99); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{path, filepath.Dir(path)} {
+		uid, gid := statOwner(t, p)
+		if uid != 99 || gid != 99 {
+			t.Fatalf("Expected %s to be owned by 99:99, got %d:%d", p, uid, gid)
+		}
+	}
+	if uid, _ := statOwner(t, dir); uid != 0 {
+		t.Fatalf("Expected the existing parent to keep its owner, got %d", uid)
+	}
+
+	// existing directories keep their owner
+	if err := MkdirAllAs(path, 0755, 98, 98); err != nil {
+		t.Fatal(err)
+	}
+	if uid, _ := statOwner(t, path); uid != 99 {
+		t.Fatalf("Expected the existing directory to keep its owner, got %d", uid)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -47,14 +48,31 @@ func (c *Client) Call(serviceMethod string, args interface{}, ret interface{}) e
 }
 
 func (c *Client) callWithRetry(serviceMethod string, args interface{}, ret interface{}, retry bool) error {
+	body, err := c.callServiceWithRetry(serviceMethod, args, retry)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(&ret)
+}
+
+// Stream calls the specified method with the specified arguments for the plugin
+// and returns the response body, for the methods which stream their result.
+// It will retry for 30 seconds if a failure occurs when calling.
+// The caller must close the returned stream.
+func (c *Client) Stream(serviceMethod string, args interface{}) (io.ReadCloser, error) {
+	return c.callServiceWithRetry(serviceMethod, args, true)
+}
+
+func (c *Client) callServiceWithRetry(serviceMethod string, args interface{}, retry bool) (io.ReadCloser, error) {
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(args); err != nil {
-		return err
+		return nil, err
 	}
 
 	req, err := http.NewRequest("POST", "/"+serviceMethod, &buf)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req.Header.Add("Accept", versionMimetype)
 	req.URL.Scheme = "http"
@@ -67,12 +85,12 @@ func (c *Client) callWithRetry(serviceMethod string, args interface{}, ret inter
 		resp, err := c.http.Do(req)
 		if err != nil {
 			if !retry {
-				return err
+				return nil, err
 			}
 
 			timeOff := backoff(retries)
 			if abort(start, timeOff) {
-				return err
+				return nil, err
 			}
 			retries++
 			logrus.Warnf("Unable to connect to plugin: %s, retrying in %v", c.addr, timeOff)
@@ -80,16 +98,16 @@ func (c *Client) callWithRetry(serviceMethod string, args interface{}, ret inter
 			continue
 		}
 
-		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
 			remoteErr, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
 			if err != nil {
-				return fmt.Errorf("Plugin Error: %s", err)
+				return nil, fmt.Errorf("Plugin Error: %s", err)
 			}
-			return fmt.Errorf("Plugin Error: %s", remoteErr)
+			return nil, fmt.Errorf("Plugin Error: %s", remoteErr)
 		}
 
-		return json.NewDecoder(resp.Body).Decode(&ret)
+		return resp.Body, nil
 	}
 }
 
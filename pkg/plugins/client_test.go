@@ -2,9 +2,11 @@ package plugins
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -65,6 +67,39 @@ func TestEchoInputOutput(t *testing.T) {
 	}
 }
 
+func TestStream(t *testing.T) {
+	addr := setupRemotePluginServer()
+	defer teardownRemotePluginServer()
+
+	mux.HandleFunc("/Test.Stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", versionMimetype)
+		io.WriteString(w, "line1\n")
+		w.(http.Flusher).Flush()
+		io.WriteString(w, "line2\n")
+	})
+	mux.HandleFunc("/Test.Fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "failure", http.StatusInternalServerError)
+	})
+
+	c, _ := NewClient(addr, tlsconfig.Options{InsecureSkipVerify: true})
+	stream, err := c.Stream("Test.Stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	out, err := ioutil.ReadAll(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "line1\nline2\n" {
+		t.Fatalf("Expected the streamed lines, got %q", out)
+	}
+
+	if _, err := c.Stream("Test.Fail", nil); err == nil || !strings.Contains(err.Error(), "failure") {
+		t.Fatalf("Expected the plugin error, got %v", err)
+	}
+}
+
 func TestBackoff(t *testing.T) {
 	cases := []struct {
 		retries    int
@@ -0,0 +1,29 @@
+package signal
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestParseSignal(t *testing.T) {
+	for raw, expected := range map[string]syscall.Signal{
+		"SIGTERM": syscall.SIGTERM,
+		"TERM":    syscall.SIGTERM,
+		"sigquit": syscall.SIGQUIT,
+		"9":       syscall.SIGKILL,
+	} {
+		sig, err := ParseSignal(raw)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing %q: %v", raw, err)
+		}
+		if sig != expected {
+			t.Fatalf("Expected %q to be parsed as %v, got %v", raw, expected, sig)
+		}
+	}
+
+	for _, raw := range []string{"0", "SIGNOPE", ""} {
+		if _, err := ParseSignal(raw); err == nil {
+			t.Fatalf("Expected an error parsing %q", raw)
+		}
+	}
+}
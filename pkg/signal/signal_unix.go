@@ -14,3 +14,6 @@ const SIGCHLD = syscall.SIGCHLD
 
 // SIGWINCH is a signal sent to a process when its controlling terminal changes its size
 const SIGWINCH = syscall.SIGWINCH
+
+// DefaultStopSignal is the syscall signal used to stop a container in unix systems.
+const DefaultStopSignal = "SIGTERM"
@@ -12,3 +12,6 @@ const (
 	SIGCHLD  = syscall.Signal(0xff)
 	SIGWINCH = syscall.Signal(0xff)
 )
+
+// DefaultStopSignal is the syscall signal used to stop a container in windows systems.
+const DefaultStopSignal = "15"
@@ -8,6 +8,7 @@ type SysInfo struct {
 
 	*cgroupMemInfo
 	*cgroupCPUInfo
+	*cgroupBlkioInfo
 
 	// Whether IPv4 forwarding is supported or not, if this was disabled, networking will not work
 	IPv4ForwardingDisabled bool
@@ -20,6 +21,9 @@ type SysInfo struct {
 
 	// Whether the cgroup has the mountpoint of "devices" or not
 	CgroupDevicesEnabled bool
+
+	// Whether the pids cgroup, which limits the number of processes, is supported or not
+	PidsLimit bool
 }
 
 type cgroupMemInfo struct {
@@ -43,3 +47,17 @@ type cgroupCPUInfo struct {
 	// Whether CPU CFS(Completely Fair Scheduler) quota is supported or not
 	CPUCfsQuota bool
 }
+
+type cgroupBlkioInfo struct {
+	// Whether Block IO read limit in bytes per second is supported or not
+	BlkioReadBpsDevice bool
+
+	// Whether Block IO write limit in bytes per second is supported or not
+	BlkioWriteBpsDevice bool
+
+	// Whether Block IO read limit in IO per second is supported or not
+	BlkioReadIOpsDevice bool
+
+	// Whether Block IO write limit in IO per second is supported or not
+	BlkioWriteIOpsDevice bool
+}
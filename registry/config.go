@@ -64,28 +64,28 @@ type ServiceConfig struct {
 // NewServiceConfig returns a new instance of ServiceConfig
 func NewServiceConfig(options *Options) *ServiceConfig {
 	if options == nil {
-		options = &Options{
-			Mirrors:            opts.NewListOpts(nil),
-			InsecureRegistries: opts.NewListOpts(nil),
-		}
+		return newServiceConfig(nil, nil)
 	}
+	return newServiceConfig(options.Mirrors.GetAll(), options.InsecureRegistries.GetAll())
+}
 
+func newServiceConfig(mirrors, insecureRegistries []string) *ServiceConfig {
 	// Localhost is by default considered as an insecure registry
 	// This is a stop-gap for people who are running a private registry on localhost (especially on Boot2docker).
 	//
 	// TODO: should we deprecate this once it is easier for people to set up a TLS registry or change
 	// daemon flags on boot2docker?
-	options.InsecureRegistries.Set("127.0.0.0/8")
+	insecureRegistries = append(append([]string{}, insecureRegistries...), "127.0.0.0/8")
 
 	config := &ServiceConfig{
 		InsecureRegistryCIDRs: make([]*netIPNet, 0),
 		IndexConfigs:          make(map[string]*IndexInfo, 0),
 		// Hack: Bypass setting the mirrors to IndexConfigs since they are going away
 		// and Mirrors are only for the official registry anyways.
-		Mirrors: options.Mirrors.GetAll(),
+		Mirrors: append([]string{}, mirrors...),
 	}
 	// Split --insecure-registry into CIDR and registry-specific settings.
-	for _, r := range options.InsecureRegistries.GetAll() {
+	for _, r := range insecureRegistries {
 		// Check if CIDR was passed to --insecure-registry
 		_, ipnet, err := net.ParseCIDR(r)
 		if err == nil {
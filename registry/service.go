@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/docker/distribution/registry/client/auth"
 	"github.com/docker/docker/cliconfig"
@@ -16,15 +17,55 @@ import (
 // Service is a registry service. It tracks configuration data such as a list
 // of mirrors.
 type Service struct {
-	Config *ServiceConfig
+	mu                 sync.RWMutex
+	config             *ServiceConfig
+	mirrors            []string
+	insecureRegistries []string
 }
 
 // NewService returns a new instance of Service ready to be
 // installed into an engine.
 func NewService(options *Options) *Service {
-	return &Service{
-		Config: NewServiceConfig(options),
+	s := &Service{}
+	if options != nil {
+		s.Reload(options.Mirrors.GetAll(), options.InsecureRegistries.GetAll())
+	} else {
+		s.Reload(nil, nil)
 	}
+	return s
+}
+
+// ServiceConfig returns the current registry configuration of the service.
+func (s *Service) ServiceConfig() *ServiceConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// Mirrors returns the registry mirrors given to the service.
+func (s *Service) Mirrors() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.mirrors
+}
+
+// InsecureRegistries returns the insecure registries given to the service,
+// without the ones considered insecure by default.
+func (s *Service) InsecureRegistries() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.insecureRegistries
+}
+
+// Reload replaces the registry mirrors and the insecure registries of the
+// service. Lookups started before are not affected.
+func (s *Service) Reload(mirrors, insecureRegistries []string) {
+	config := newServiceConfig(mirrors, insecureRegistries)
+	s.mu.Lock()
+	s.config = config
+	s.mirrors = append([]string{}, mirrors...)
+	s.insecureRegistries = append([]string{}, insecureRegistries...)
+	s.mu.Unlock()
 }
 
 // Auth contacts the public registry with the provided credentials,
@@ -71,12 +112,12 @@ func (s *Service) Search(term string, authConfig *cliconfig.AuthConfig, headers
 // ResolveRepository splits a repository name into its components
 // and configuration of the associated registry.
 func (s *Service) ResolveRepository(name string) (*RepositoryInfo, error) {
-	return s.Config.NewRepositoryInfo(name)
+	return s.ServiceConfig().NewRepositoryInfo(name)
 }
 
 // ResolveIndex takes indexName and returns index info
 func (s *Service) ResolveIndex(name string) (*IndexInfo, error) {
-	return s.Config.NewIndexInfo(name)
+	return s.ServiceConfig().NewIndexInfo(name)
 }
 
 // APIEndpoint represents a remote API endpoint
@@ -98,7 +139,7 @@ func (e APIEndpoint) ToV1Endpoint(metaHeaders http.Header) (*Endpoint, error) {
 
 // TLSConfig constructs a client TLS configuration based on server defaults
 func (s *Service) TLSConfig(hostname string) (*tls.Config, error) {
-	return newTLSConfig(hostname, s.Config.isSecureIndex(hostname))
+	return newTLSConfig(hostname, s.ServiceConfig().isSecureIndex(hostname))
 }
 
 func (s *Service) tlsConfigForMirror(mirror string) (*tls.Config, error) {
@@ -117,7 +158,7 @@ func (s *Service) LookupEndpoints(repoName string) (endpoints []APIEndpoint, err
 	tlsConfig := &cfg
 	if strings.HasPrefix(repoName, DefaultNamespace+"/") {
 		// v2 mirrors
-		for _, mirror := range s.Config.Mirrors {
+		for _, mirror := range s.ServiceConfig().Mirrors {
 			mirrorTLSConfig, err := s.tlsConfigForMirror(mirror)
 			if err != nil {
 				return nil, err
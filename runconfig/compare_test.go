@@ -2,6 +2,7 @@ package runconfig
 
 import (
 	"testing"
+	"time"
 
 	"github.com/docker/docker/pkg/nat"
 )
@@ -41,6 +42,9 @@ func TestCompare(t *testing.T) {
 	labels1 := map[string]string{"LABEL1": "value1", "LABEL2": "value2"}
 	labels2 := map[string]string{"LABEL1": "value1", "LABEL2": "value3"}
 	labels3 := map[string]string{"LABEL1": "value1", "LABEL2": "value2", "LABEL3": "value3"}
+	health1 := &HealthConfig{Test: []string{"CMD-SHELL", "true"}, Interval: time.Second}
+	health2 := &HealthConfig{Test: []string{"CMD-SHELL", "false"}, Interval: time.Second}
+	health3 := &HealthConfig{Test: []string{"CMD-SHELL", "true"}, Interval: time.Minute}
 
 	sameConfigs := map[*Config]*Config{
 		// Empty config
@@ -71,6 +75,8 @@ func TestCompare(t *testing.T) {
 		&Config{Entrypoint: entrypoint1}: {Entrypoint: entrypoint1},
 		// only volumes
 		&Config{Volumes: volumes1}: {Volumes: volumes1},
+		// only healthcheck
+		&Config{Healthcheck: health1}: {Healthcheck: health1},
 	}
 	differentConfigs := map[*Config]*Config{
 		nil: nil,
@@ -110,6 +116,12 @@ func TestCompare(t *testing.T) {
 		&Config{Volumes: volumes1}: {Volumes: volumes2},
 		// not the same number of labels
 		&Config{Volumes: volumes1}: {Volumes: volumes3},
+		// only healthcheck
+		&Config{Healthcheck: health1}: {Healthcheck: health2},
+		// not the same interval
+		&Config{Healthcheck: health1}: {Healthcheck: health3},
+		// healthcheck only set on one side
+		&Config{Healthcheck: health1}: {},
 	}
 	for config1, config2 := range sameConfigs {
 		if !Compare(config1, config2) {
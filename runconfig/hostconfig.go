@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"io"
 	"strings"
+	"time"
 
+	"github.com/docker/docker/pkg/blkiodev"
 	"github.com/docker/docker/pkg/nat"
 	"github.com/docker/docker/pkg/ulimit"
 )
@@ -120,6 +122,10 @@ type DeviceMapping struct {
 type RestartPolicy struct {
 	Name              string
 	MaximumRetryCount int
+
+	// Zero means to use the default. Durations are expressed as integer nanoseconds.
+	MaximumBackoff time.Duration `json:",omitempty"` // MaximumBackoff is the longest delay between two restarts, unlimited by default.
+	ResetWindow    time.Duration `json:",omitempty"` // ResetWindow is the running time after which the delay is reset, 10 seconds by default.
 }
 
 // IsNone indicates whether the container has the "no" restart policy.
@@ -140,6 +146,13 @@ func (rp *RestartPolicy) IsOnFailure() bool {
 	return rp.Name == "on-failure"
 }
 
+// IsUnlessStopped indicates whether the container has the "unless-stopped" restart policy.
+// This means the container will automatically restart unless it was stopped by the user,
+// also when the daemon restarts.
+func (rp *RestartPolicy) IsUnlessStopped() bool {
+	return rp.Name == "unless-stopped"
+}
+
 // LogConfig represents the logging configuration of the container.
 type LogConfig struct {
 	Type   string
@@ -260,42 +273,73 @@ func NewCapList(caps []string) *CapList {
 // Here, "non-portable" means "dependent of the host we are running on".
 // Portable information *should* appear in Config.
 type HostConfig struct {
-	Binds            []string         // List of volume bindings for this container
-	ContainerIDFile  string           // File (path) where the containerId is written
-	LxcConf          *LxcConfig       // Additional lxc configuration
-	Memory           int64            // Memory limit (in bytes)
-	MemorySwap       int64            // Total memory usage (memory + swap); set `-1` to disable swap
-	CPUShares        int64            `json:"CpuShares"` // CPU shares (relative weight vs. other containers)
-	CPUPeriod        int64            `json:"CpuPeriod"` // CPU CFS (Completely Fair Scheduler) period
-	CpusetCpus       string           // CpusetCpus 0-2, 0,1
-	CpusetMems       string           // CpusetMems 0-2, 0,1
-	CPUQuota         int64            `json:"CpuQuota"` // CPU CFS (Completely Fair Scheduler) quota
-	BlkioWeight      int64            // Block IO weight (relative weight vs. other containers)
-	OomKillDisable   bool             // Whether to disable OOM Killer or not
-	MemorySwappiness *int64           // Tuning container memory swappiness behaviour
-	Privileged       bool             // Is the container in privileged mode
-	PortBindings     nat.PortMap      // Port mapping between the exposed port (container) and the host
-	Links            []string         // List of links (in the name:alias form)
-	PublishAllPorts  bool             // Should docker publish all exposed port for the container
-	DNS              []string         `json:"Dns"`       // List of DNS server to lookup
-	DNSSearch        []string         `json:"DnsSearch"` // List of DNSSearch to look for
-	ExtraHosts       []string         // List of extra hosts
-	VolumesFrom      []string         // List of volumes to take from other container
-	Devices          []DeviceMapping  // List of devices to map inside the container
-	NetworkMode      NetworkMode      // Network namespace to use for the container
-	IpcMode          IpcMode          // IPC namespace to use for the container
-	PidMode          PidMode          // PID namespace to use for the container
-	UTSMode          UTSMode          // UTS namespace to use for the container
-	CapAdd           *CapList         // List of kernel capabilities to add to the container
-	CapDrop          *CapList         // List of kernel capabilities to remove from the container
-	GroupAdd         []string         // List of additional groups that the container process will run as
-	RestartPolicy    RestartPolicy    // Restart policy to be used for the container
-	SecurityOpt      []string         // List of string values to customize labels for MLS systems, such as SELinux.
-	ReadonlyRootfs   bool             // Is the container root filesystem in read-only
-	Ulimits          []*ulimit.Ulimit // List of ulimits to be set in the container
-	LogConfig        LogConfig        // Configuration of the logs for this container
-	CgroupParent     string           // Parent cgroup.
-	ConsoleSize      [2]int           // Initial console size on Windows
+	Binds                []string                   // List of volume bindings for this container
+	ContainerIDFile      string                     // File (path) where the containerId is written
+	LxcConf              *LxcConfig                 // Additional lxc configuration
+	Memory               int64                      // Memory limit (in bytes)
+	MemorySwap           int64                      // Total memory usage (memory + swap); set `-1` to disable swap
+	CPUShares            int64                      `json:"CpuShares"` // CPU shares (relative weight vs. other containers)
+	CPUPeriod            int64                      `json:"CpuPeriod"` // CPU CFS (Completely Fair Scheduler) period
+	CpusetCpus           string                     // CpusetCpus 0-2, 0,1
+	CpusetMems           string                     // CpusetMems 0-2, 0,1
+	CPUQuota             int64                      `json:"CpuQuota"` // CPU CFS (Completely Fair Scheduler) quota
+	BlkioWeight          int64                      // Block IO weight (relative weight vs. other containers)
+	BlkioDeviceReadBps   []*blkiodev.ThrottleDevice // Limit read rate (bytes per second) from a device
+	BlkioDeviceWriteBps  []*blkiodev.ThrottleDevice // Limit write rate (bytes per second) to a device
+	BlkioDeviceReadIOps  []*blkiodev.ThrottleDevice // Limit read rate (IO per second) from a device
+	BlkioDeviceWriteIOps []*blkiodev.ThrottleDevice // Limit write rate (IO per second) to a device
+	OomKillDisable       bool                       // Whether to disable OOM Killer or not
+	MemorySwappiness     *int64                     // Tuning container memory swappiness behaviour
+	PidsLimit            int64                      // Maximum number of processes in the container; -1 for unlimited
+	Privileged           bool                       // Is the container in privileged mode
+	PortBindings         nat.PortMap                // Port mapping between the exposed port (container) and the host
+	Links                []string                   // List of links (in the name:alias form)
+	PublishAllPorts      bool                       // Should docker publish all exposed port for the container
+	DNS                  []string                   `json:"Dns"`       // List of DNS server to lookup
+	DNSSearch            []string                   `json:"DnsSearch"` // List of DNSSearch to look for
+	ExtraHosts           []string                   // List of extra hosts
+	VolumesFrom          []string                   // List of volumes to take from other container
+	Devices              []DeviceMapping            // List of devices to map inside the container
+	NetworkMode          NetworkMode                // Network namespace to use for the container
+	IpcMode              IpcMode                    // IPC namespace to use for the container
+	PidMode              PidMode                    // PID namespace to use for the container
+	UTSMode              UTSMode                    // UTS namespace to use for the container
+	CapAdd               *CapList                   // List of kernel capabilities to add to the container
+	CapDrop              *CapList                   // List of kernel capabilities to remove from the container
+	GroupAdd             []string                   // List of additional groups that the container process will run as
+	RestartPolicy        RestartPolicy              // Restart policy to be used for the container
+	AutoRemove           bool                       // Automatically remove the container when it exits
+	SecurityOpt          []string                   // List of string values to customize labels for MLS systems, such as SELinux.
+	ReadonlyRootfs       bool                       // Is the container root filesystem in read-only
+	Ulimits              []*ulimit.Ulimit           // List of ulimits to be set in the container
+	LogConfig            LogConfig                  // Configuration of the logs for this container
+	CgroupParent         string                     // Parent cgroup.
+	ConsoleSize          [2]int                     // Initial console size on Windows
+}
+
+// UpdateConfig holds the resources of a container that can be changed with
+// docker update. The fields left to their zero value are not changed.
+type UpdateConfig struct {
+	Memory      int64  // Memory limit (in bytes)
+	MemorySwap  int64  // Total memory usage (memory + swap); set `-1` to disable swap
+	CPUShares   int64  `json:"CpuShares"` // CPU shares (relative weight vs. other containers)
+	CPUPeriod   int64  `json:"CpuPeriod"` // CPU CFS (Completely Fair Scheduler) period
+	CpusetCpus  string // CpusetCpus 0-2, 0,1
+	CpusetMems  string // CpusetMems 0-2, 0,1
+	CPUQuota    int64  `json:"CpuQuota"` // CPU CFS (Completely Fair Scheduler) quota
+	BlkioWeight int64  // Block IO weight (relative weight vs. other containers)
+}
+
+// CriuConfig holds the options of a checkpoint or a restore of a container
+// with docker checkpoint and docker restore.
+type CriuConfig struct {
+	ImagesDirectory         string // Directory of the checkpoint images, under the container root by default
+	WorkDirectory           string // Directory of the logs of CRIU
+	LeaveRunning            bool   // Leave the container running after the checkpoint
+	TCPEstablished          bool   `json:"TcpEstablished"` // Checkpoint and restore established TCP connections
+	ExternalUnixConnections bool   // Allow external unix connections
+	ShellJob                bool   // Allow shell jobs
+	FileLocks               bool   // Handle file locks
 }
 
 // MergeConfigs merges the specified container Config and HostConfig.
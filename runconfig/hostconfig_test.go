@@ -15,20 +15,20 @@ func TestNetworkModeTest(t *testing.T) {
 		"something:weird":          {true, false, false, false, false, false},
 		"bridge":                   {true, true, false, false, false, false},
 		DefaultDaemonNetworkMode(): {true, true, false, false, false, false},
-		"host":           {false, false, true, false, false, false},
-		"container:name": {false, false, false, true, false, false},
-		"none":           {true, false, false, false, true, false},
-		"default":        {true, false, false, false, false, true},
+		"host":                     {false, false, true, false, false, false},
+		"container:name":           {false, false, false, true, false, false},
+		"none":                     {true, false, false, false, true, false},
+		"default":                  {true, false, false, false, false, true},
 	}
 	networkModeNames := map[NetworkMode]string{
 		"":                         "",
 		"something:weird":          "",
 		"bridge":                   "bridge",
 		DefaultDaemonNetworkMode(): "bridge",
-		"host":           "host",
-		"container:name": "container",
-		"none":           "none",
-		"default":        "default",
+		"host":                     "host",
+		"container:name":           "container",
+		"none":                     "none",
+		"default":                  "default",
 	}
 	for networkMode, state := range networkModes {
 		if networkMode.IsPrivate() != state[0] {
@@ -140,12 +140,13 @@ func TestPidModeTest(t *testing.T) {
 
 func TestRestartPolicy(t *testing.T) {
 	restartPolicies := map[RestartPolicy][]bool{
-		// none, always, failure
-		RestartPolicy{}:                {false, false, false},
-		RestartPolicy{"something", 0}:  {false, false, false},
-		RestartPolicy{"no", 0}:         {true, false, false},
-		RestartPolicy{"always", 0}:     {false, true, false},
-		RestartPolicy{"on-failure", 0}: {false, false, true},
+		// none, always, failure, unless-stopped
+		RestartPolicy{}:                       {false, false, false, false},
+		RestartPolicy{Name: "something"}:      {false, false, false, false},
+		RestartPolicy{Name: "no"}:             {true, false, false, false},
+		RestartPolicy{Name: "always"}:         {false, true, false, false},
+		RestartPolicy{Name: "on-failure"}:     {false, false, true, false},
+		RestartPolicy{Name: "unless-stopped"}: {false, false, false, true},
 	}
 	for restartPolicy, state := range restartPolicies {
 		if restartPolicy.IsNone() != state[0] {
@@ -157,6 +158,9 @@ func TestRestartPolicy(t *testing.T) {
 		if restartPolicy.IsOnFailure() != state[2] {
 			t.Fatalf("RestartPolicy.IsOnFailure for %v should have been %v but was %v", restartPolicy, state[2], restartPolicy.IsOnFailure())
 		}
+		if restartPolicy.IsUnlessStopped() != state[3] {
+			t.Fatalf("RestartPolicy.IsUnlessStopped for %v should have been %v but was %v", restartPolicy, state[3], restartPolicy.IsUnlessStopped())
+		}
 	}
 }
 
@@ -182,8 +186,8 @@ func TestLxcConfigMarshalJSON(t *testing.T) {
 
 func TestLxcConfigUnmarshalJSON(t *testing.T) {
 	keyvaluePairs := map[string][]KeyValuePair{
-		"":   {{"key1", "value1"}},
-		"[]": {},
+		"":                                  {{"key1", "value1"}},
+		"[]":                                {},
 		`[{"Key":"key2","Value":"value2"}]`: {{"key2", "value2"}},
 	}
 	for json, expectedParts := range keyvaluePairs {
@@ -2,6 +2,7 @@ package runconfig
 
 import (
 	"testing"
+	"time"
 
 	"github.com/docker/docker/pkg/nat"
 )
@@ -81,3 +82,41 @@ func TestMerge(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeHealthcheck(t *testing.T) {
+	configImage := &Config{
+		Healthcheck: &HealthConfig{
+			Test:     []string{"CMD-SHELL", "true"},
+			Interval: time.Minute,
+			Retries:  5,
+		},
+	}
+	configUser := &Config{
+		Healthcheck: &HealthConfig{
+			Interval: time.Second,
+		},
+	}
+
+	if err := Merge(configUser, configImage); err != nil {
+		t.Fatal(err)
+	}
+
+	health := configUser.Healthcheck
+	if len(health.Test) != 2 || health.Test[1] != "true" {
+		t.Fatalf("Expected the image healthcheck test to be inherited, got %v", health.Test)
+	}
+	if health.Interval != time.Second {
+		t.Fatalf("Expected the user interval to be kept, got %v", health.Interval)
+	}
+	if health.Retries != 5 {
+		t.Fatalf("Expected the image retries to be inherited, got %d", health.Retries)
+	}
+
+	configUser = &Config{}
+	if err := Merge(configUser, configImage); err != nil {
+		t.Fatal(err)
+	}
+	if configUser.Healthcheck != configImage.Healthcheck {
+		t.Fatalf("Expected the image healthcheck to be inherited, got %v", configUser.Healthcheck)
+	}
+}
@@ -3,8 +3,10 @@ package runconfig
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/pkg/nat"
@@ -298,6 +300,18 @@ func TestParseWithMemory(t *testing.T) {
 	}
 }
 
+func TestParseWithStopSignal(t *testing.T) {
+	if config, _ := mustParse(t, ""); config.StopSignal != "" {
+		t.Fatalf("Expected no StopSignal when --stop-signal is not set, got %q", config.StopSignal)
+	}
+	if config, _ := mustParse(t, "--stop-signal=SIGQUIT"); config.StopSignal != "SIGQUIT" {
+		t.Fatalf("Expected the config to have 'SIGQUIT' as StopSignal, got %q", config.StopSignal)
+	}
+	if _, _, err := parse(t, "--stop-signal=SIGNOPE"); err == nil || err.Error() != "Invalid signal: SIGNOPE" {
+		t.Fatalf("Expected an invalid signal error, got %v", err)
+	}
+}
+
 func TestParseWithMemorySwap(t *testing.T) {
 	invalidMemory := "--memory-swap=invalid"
 	validMemory := "--memory-swap=1G"
@@ -313,6 +327,22 @@ func TestParseWithMemorySwap(t *testing.T) {
 	}
 }
 
+func TestParseThrottleDevices(t *testing.T) {
+	_, hostconfig := mustParse(t, "--device-read-bps=/dev/sda:1mb --device-read-bps=/dev/sdb:512 --device-write-iops=/dev/sda:100")
+	if len(hostconfig.BlkioDeviceReadBps) != 2 || hostconfig.BlkioDeviceReadBps[0].String() != "/dev/sda:1048576" || hostconfig.BlkioDeviceReadBps[1].String() != "/dev/sdb:512" {
+		t.Fatalf("Expected 2 read bps devices, got %v", hostconfig.BlkioDeviceReadBps)
+	}
+	if len(hostconfig.BlkioDeviceWriteIOps) != 1 || hostconfig.BlkioDeviceWriteIOps[0].String() != "/dev/sda:100" {
+		t.Fatalf("Expected 1 write iops device, got %v", hostconfig.BlkioDeviceWriteIOps)
+	}
+	if hostconfig.BlkioDeviceWriteBps != nil || hostconfig.BlkioDeviceReadIOps != nil {
+		t.Fatalf("Expected no other throttle devices, got %v %v", hostconfig.BlkioDeviceWriteBps, hostconfig.BlkioDeviceReadIOps)
+	}
+	if _, _, err := parse(t, "--device-write-bps=sda:1mb"); err == nil {
+		t.Fatal("Expected an error for a device without a /dev path")
+	}
+}
+
 func TestParseHostname(t *testing.T) {
 	hostname := "--hostname=hostname"
 	hostnameWithDomain := "--hostname=hostname.domainname"
@@ -458,6 +488,7 @@ func TestParseRestartPolicy(t *testing.T) {
 		"something":          "invalid restart policy something",
 		"always:2":           "maximum restart count not valid with restart policy of \"always\"",
 		"always:2:3":         "maximum restart count not valid with restart policy of \"always\"",
+		"unless-stopped:2":   "maximum restart count not valid with restart policy of \"unless-stopped\"",
 		"on-failure:invalid": `strconv.ParseInt: parsing "invalid": invalid syntax`,
 		"on-failure:2:5":     "restart count format is not valid, usage: 'on-failure:N' or 'on-failure'",
 	}
@@ -471,6 +502,9 @@ func TestParseRestartPolicy(t *testing.T) {
 			Name:              "on-failure",
 			MaximumRetryCount: 1,
 		},
+		"unless-stopped": {
+			Name: "unless-stopped",
+		},
 	}
 	for restart, expectedError := range invalids {
 		if _, _, _, err := parseRun([]string{fmt.Sprintf("--restart=%s", restart), "img", "cmd"}); err == nil || err.Error() != expectedError {
@@ -488,6 +522,26 @@ func TestParseRestartPolicy(t *testing.T) {
 	}
 }
 
+func TestParseRestartBackoff(t *testing.T) {
+	_, hostconfig, _, err := parseRun([]string{"--restart=always", "--restart-max-backoff=1m", "--restart-reset-window=30s", "img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostconfig.RestartPolicy.MaximumBackoff != time.Minute || hostconfig.RestartPolicy.ResetWindow != 30*time.Second {
+		t.Fatalf("Unexpected restart policy %+v", hostconfig.RestartPolicy)
+	}
+
+	for _, args := range [][]string{
+		{"--restart-max-backoff=1m"},
+		{"--restart=no", "--restart-reset-window=1m"},
+		{"--restart=always", "--restart-max-backoff=-1s"},
+	} {
+		if _, _, _, err := parseRun(append(args, "img", "cmd")); err == nil {
+			t.Fatalf("Expected an error for %v", args)
+		}
+	}
+}
+
 func TestParseLoggingOpts(t *testing.T) {
 	// logging opts ko
 	if _, _, _, err := parseRun([]string{"--log-driver=none", "--log-opt=anything", "img", "cmd"}); err == nil || err.Error() != "Invalid logging opts for driver none" {
@@ -503,6 +557,42 @@ func TestParseLoggingOpts(t *testing.T) {
 	}
 }
 
+func TestParseSeccompProfile(t *testing.T) {
+	f, err := ioutil.TempFile("", "seccomp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(`{
+	"defaultAction": "SCMP_ACT_ALLOW",
+	"syscalls": [{"name": "chmod", "action": "SCMP_ACT_ERRNO"}]
+}`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	_, hostconfig, _, err := parseRun([]string{"--security-opt=seccomp:" + f.Name(), "--security-opt=label:disable", "img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `seccomp={"defaultAction":"SCMP_ACT_ALLOW","syscalls":[{"name":"chmod","action":"SCMP_ACT_ERRNO"}]}`
+	if len(hostconfig.SecurityOpt) != 2 || hostconfig.SecurityOpt[0] != expected || hostconfig.SecurityOpt[1] != "label:disable" {
+		t.Fatalf("Expected the content of the seccomp profile to be sent, got %v", hostconfig.SecurityOpt)
+	}
+
+	_, hostconfig, _, err = parseRun([]string{"--security-opt=seccomp=unconfined", "img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostconfig.SecurityOpt[0] != "seccomp=unconfined" {
+		t.Fatalf("Expected seccomp=unconfined, got %v", hostconfig.SecurityOpt)
+	}
+
+	if _, _, _, err := parseRun([]string{"--security-opt=seccomp=/nonexistent.json", "img", "cmd"}); err == nil {
+		t.Fatal("Expected an error for a missing seccomp profile")
+	}
+}
+
 func TestParseEnvfileVariables(t *testing.T) {
 	// env ko
 	if _, _, _, err := parseRun([]string{"--env-file=nonexistent", "img", "cmd"}); err == nil || err.Error() != "open nonexistent: no such file or directory" {
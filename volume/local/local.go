@@ -12,6 +12,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/volume"
 )
 
@@ -27,11 +28,13 @@ var oldVfsDir = filepath.Join("vfs", "dir")
 
 // New instantiates a new Root instance with the provided scope. Scope
 // is the base path that the Root instance uses to store its
-// volumes. The base path is created here if it does not exist.
-func New(scope string) (*Root, error) {
+// volumes. The base path is created here if it does not exist. The data
+// directories of the volumes are owned by rootUID and rootGID, the
+// remapped root of the daemon.
+func New(scope string, rootUID, rootGID int) (*Root, error) {
 	rootDirectory := filepath.Join(scope, volumesPathName)
 
-	if err := os.MkdirAll(rootDirectory, 0700); err != nil {
+	if err := idtools.MkdirAllAs(rootDirectory, 0700, rootUID, rootGID); err != nil {
 		return nil, err
 	}
 
@@ -39,6 +42,8 @@ func New(scope string) (*Root, error) {
 		scope:   scope,
 		path:    rootDirectory,
 		volumes: make(map[string]*localVolume),
+		rootUID: rootUID,
+		rootGID: rootGID,
 	}
 
 	dirs, err := ioutil.ReadDir(rootDirectory)
@@ -65,6 +70,8 @@ type Root struct {
 	scope   string
 	path    string
 	volumes map[string]*localVolume
+	rootUID int
+	rootGID int
 }
 
 // DataPath returns the constructed path of this volume.
@@ -85,29 +92,30 @@ func (r *Root) Create(name string) (volume.Volume, error) {
 	defer r.m.Unlock()
 
 	v, exists := r.volumes[name]
-	if !exists {
-		path := r.DataPath(name)
-		if err := os.MkdirAll(path, 0755); err != nil {
-			if os.IsExist(err) {
-				return nil, fmt.Errorf("volume already exists under %s", filepath.Dir(path))
-			}
-			return nil, err
-		}
-		v = &localVolume{
-			driverName: r.Name(),
-			name:       name,
-			path:       path,
+	if exists {
+		return v, nil
+	}
+
+	path := r.DataPath(name)
+	if err := idtools.MkdirAllAs(path, 0755, r.rootUID, r.rootGID); err != nil {
+		if os.IsExist(err) {
+			return nil, fmt.Errorf("volume already exists under %s", filepath.Dir(path))
 		}
-		r.volumes[name] = v
+		return nil, err
+	}
+	v = &localVolume{
+		driverName: r.Name(),
+		name:       name,
+		path:       path,
 	}
-	v.use()
+	r.volumes[name] = v
 	return v, nil
 }
 
 // Remove removes the specified volume and all underlying data. If the
 // given volume does not belong to this driver and an error is
-// returned. The volume is reference counted, if all references are
-// not released then the volume is not removed.
+// returned. Reference counting is left to the caller: the volume is
+// removed unconditionally.
 func (r *Root) Remove(v volume.Volume) error {
 	r.m.Lock()
 	defer r.m.Unlock()
@@ -115,24 +123,43 @@ func (r *Root) Remove(v volume.Volume) error {
 	if !ok {
 		return errors.New("unknown volume type")
 	}
-	lv.release()
-	if lv.usedCount == 0 {
-		realPath, err := filepath.EvalSymlinks(lv.path)
-		if err != nil {
-			return err
-		}
-		if !r.scopedPath(realPath) {
-			return fmt.Errorf("Unable to remove a directory of out the Docker root: %s", realPath)
-		}
 
-		if err := os.RemoveAll(realPath); err != nil {
-			return err
-		}
+	realPath, err := filepath.EvalSymlinks(lv.path)
+	if err != nil {
+		return err
+	}
+	if !r.scopedPath(realPath) {
+		return fmt.Errorf("Unable to remove a directory of out the Docker root: %s", realPath)
+	}
 
-		delete(r.volumes, lv.name)
-		return os.RemoveAll(filepath.Dir(lv.path))
+	if err := os.RemoveAll(realPath); err != nil {
+		return err
 	}
-	return nil
+
+	delete(r.volumes, lv.name)
+	return os.RemoveAll(filepath.Dir(lv.path))
+}
+
+// List lists all the volumes managed by this driver.
+func (r *Root) List() []volume.Volume {
+	r.m.Lock()
+	defer r.m.Unlock()
+	var ls []volume.Volume
+	for _, v := range r.volumes {
+		ls = append(ls, v)
+	}
+	return ls
+}
+
+// Get looks up the volume for the given name and returns it if found.
+func (r *Root) Get(name string) (volume.Volume, error) {
+	r.m.Lock()
+	v, exists := r.volumes[name]
+	r.m.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("no such volume: %s", name)
+	}
+	return v, nil
 }
 
 // scopedPath verifies that the path where the volume is located
@@ -154,8 +181,6 @@ func (r *Root) scopedPath(realPath string) bool {
 // localVolume implements the Volume interface from the volume package and
 // represents the volumes created by Root.
 type localVolume struct {
-	m         sync.Mutex
-	usedCount int
 	// unique name of the volume
 	name string
 	// path is the path on the host where the data lives
@@ -188,15 +213,3 @@ func (v *localVolume) Mount() (string, error) {
 func (v *localVolume) Unmount() error {
 	return nil
 }
-
-func (v *localVolume) use() {
-	v.m.Lock()
-	v.usedCount++
-	v.m.Unlock()
-}
-
-func (v *localVolume) release() {
-	v.m.Lock()
-	v.usedCount--
-	v.m.Unlock()
-}
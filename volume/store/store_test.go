@@ -0,0 +1,128 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/docker/docker/volume"
+	"github.com/docker/docker/volume/drivers"
+)
+
+type fakeVolume struct {
+	name       string
+	driverName string
+}
+
+func (f *fakeVolume) Name() string           { return f.name }
+func (f *fakeVolume) DriverName() string     { return f.driverName }
+func (f *fakeVolume) Path() string           { return "/" + f.name }
+func (f *fakeVolume) Mount() (string, error) { return f.Path(), nil }
+func (f *fakeVolume) Unmount() error         { return nil }
+
+type fakeDriver struct {
+	name    string
+	removed []string
+}
+
+func (d *fakeDriver) Name() string { return d.name }
+func (d *fakeDriver) Create(name string) (volume.Volume, error) {
+	return &fakeVolume{name: name, driverName: d.name}, nil
+}
+func (d *fakeDriver) Remove(v volume.Volume) error {
+	d.removed = append(d.removed, v.Name())
+	return nil
+}
+
+func TestCreate(t *testing.T) {
+	d := &fakeDriver{name: "fake"}
+	volumedrivers.Register(d, d.Name())
+	defer volumedrivers.Unregister(d.Name())
+
+	s := New()
+	v, err := s.Create("fake1", "fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Name() != "fake1" {
+		t.Fatalf("Expected fake1 volume, got %v", v)
+	}
+	if l := s.List(); len(l) != 1 {
+		t.Fatalf("Expected 1 volume in the store, got %v: %v", len(l), l)
+	}
+
+	v2, err := s.Create("fake1", "fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v2 != v {
+		t.Fatalf("Expected the existing volume to be returned, got %v", v2)
+	}
+
+	if _, err := s.Create("none", "none"); err == nil {
+		t.Fatalf("Expected unknown driver error, got nil")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	d := &fakeDriver{name: "fake"}
+	volumedrivers.Register(d, d.Name())
+	defer volumedrivers.Unregister(d.Name())
+
+	s := New()
+	v, err := s.Create("fake1", "fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Increment(v)
+	if err := s.Remove(v); err != ErrVolumeInUse {
+		t.Fatalf("Expected ErrVolumeInUse error, got %v", err)
+	}
+	s.Decrement(v)
+
+	if err := s.Remove(v); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.removed) != 1 || d.removed[0] != "fake1" {
+		t.Fatalf("Expected the driver to remove fake1, got %v", d.removed)
+	}
+	if l := s.List(); len(l) != 0 {
+		t.Fatalf("Expected 0 volumes in the store, got %v, %v", len(l), l)
+	}
+	if _, err := s.Get("fake1"); err != ErrNoSuchVolume {
+		t.Fatalf("Expected ErrNoSuchVolume error, got %v", err)
+	}
+}
+
+func TestIncrementDecrement(t *testing.T) {
+	s := New()
+	v := &fakeVolume{name: "fake1", driverName: "fake"}
+
+	s.Increment(v)
+	s.Increment(v)
+	if c := s.Count(v); c != 2 {
+		t.Fatalf("Expected 2 references, got %v", c)
+	}
+
+	s.Decrement(v)
+	s.Decrement(v)
+	s.Decrement(v)
+	if c := s.Count(v); c != 0 {
+		t.Fatalf("Expected 0 references, got %v", c)
+	}
+}
+
+func TestFilterByDriver(t *testing.T) {
+	s := New()
+	s.AddAll([]volume.Volume{
+		&fakeVolume{name: "fake1", driverName: "fake"},
+		&fakeVolume{name: "fake2", driverName: "noop"},
+		&fakeVolume{name: "fake3", driverName: "fake"},
+	})
+
+	if l := s.FilterByDriver("fake"); len(l) != 2 {
+		t.Fatalf("Expected 2 volumes, got %v, %v", len(l), l)
+	}
+	if l := s.FilterByDriver("noop"); len(l) != 1 {
+		t.Fatalf("Expected 1 volume, got %v, %v", len(l), l)
+	}
+}